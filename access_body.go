@@ -0,0 +1,130 @@
+package zerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/rs/zerolog"
+)
+
+// DefaultBodyLimit is the default number of bytes of a body captured in access logs
+const DefaultBodyLimit = 4096
+
+// DefaultBodyContentTypes are the content types of bodies captured in access logs by default.
+// A type ending with '/' matches every subtype, e.g. "text/" matches "text/plain".
+var DefaultBodyContentTypes = []string{"application/json", "application/x-www-form-urlencoded", "text/"}
+
+// bodyCapture selects the request and response bodies added to access logs
+type bodyCapture struct {
+	request      bool
+	response     bool
+	limit        int
+	contentTypes []string
+}
+
+// WithAccessLogRequestBody adds the request body to access logs as the request_body field,
+// when its content type is allowed
+func WithAccessLogRequestBody() AccessLogOpt {
+	return func(opts *AccessLogOptions) {
+		opts.bodies.request = true
+	}
+}
+
+// WithAccessLogResponseBody adds the response body to access logs as the response_body field,
+// when its content type is allowed
+func WithAccessLogResponseBody() AccessLogOpt {
+	return func(opts *AccessLogOptions) {
+		opts.bodies.response = true
+	}
+}
+
+// WithAccessLogBodyLimit allows to specify the number of bytes of a body that are captured. Longer bodies are truncated
+// and logged as strings with a <field>_truncated field. By default, it is set to DefaultBodyLimit.
+func WithAccessLogBodyLimit(limit int) AccessLogOpt {
+	return func(opts *AccessLogOptions) {
+		opts.bodies.limit = limit
+	}
+}
+
+// WithAccessLogBodyContentTypes allows to specify the content types of captured bodies.
+// By default, it is set to DefaultBodyContentTypes.
+func WithAccessLogBodyContentTypes(contentTypes ...string) AccessLogOpt {
+	return func(opts *AccessLogOptions) {
+		opts.bodies.contentTypes = contentTypes
+	}
+}
+
+func (b *bodyCapture) appendFields(e *zerolog.Event, c *app.RequestContext) {
+	if b.request && !c.Request.IsBodyStream() {
+		b.appendBody(e, "request_body", string(c.Request.Header.ContentType()), c.Request.Body())
+	}
+	if b.response && !c.Response.IsBodyStream() {
+		b.appendBody(e, "response_body", string(c.Response.Header.ContentType()), c.Response.Body())
+	}
+}
+
+// appendBody adds body to the event, as raw JSON when it is complete valid JSON and as a string otherwise
+func (b *bodyCapture) appendBody(e *zerolog.Event, key, contentType string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+
+	mediaType, ok := b.allowed(contentType)
+	if !ok {
+		return
+	}
+
+	if len(body) > b.limit {
+		e.Bytes(key, truncateUTF8(body, b.limit))
+		e.Bool(key+"_truncated", true)
+		return
+	}
+
+	// compacted so that an embedded body does not break the line of the log entry
+	var compacted bytes.Buffer
+	if isJSONMediaType(mediaType) && json.Compact(&compacted, body) == nil {
+		e.RawJSON(key, compacted.Bytes())
+		return
+	}
+
+	e.Bytes(key, body)
+}
+
+// allowed returns the media type of contentType and whether bodies of that type are captured
+func (b *bodyCapture) allowed(contentType string) (string, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", false
+	}
+
+	for _, allowed := range b.contentTypes {
+		allowed = strings.ToLower(allowed)
+		if mediaType == allowed || strings.HasSuffix(allowed, "/") && strings.HasPrefix(mediaType, allowed) {
+			return mediaType, true
+		}
+	}
+
+	return mediaType, false
+}
+
+// isJSONMediaType reports whether the media type is JSON, including structured syntax suffixes like application/problem+json
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// truncateUTF8 returns at most limit bytes of body without splitting a UTF-8 sequence
+func truncateUTF8(body []byte, limit int) []byte {
+	if limit <= 0 {
+		return nil
+	}
+
+	i := limit
+	for i > 0 && i > limit-4 && body[i]&0xc0 == 0x80 {
+		i--
+	}
+
+	return body[:i]
+}
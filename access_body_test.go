@@ -0,0 +1,110 @@
+package zerolog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/cloudwego/hertz/pkg/common/json"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/cloudwego/hertz/pkg/route"
+	"github.com/stretchr/testify/assert"
+)
+
+type BodyLog struct {
+	RequestBody           string `json:"request_body"`
+	RequestBodyTruncated  bool   `json:"request_body_truncated"`
+	ResponseBody          string `json:"response_body"`
+	ResponseBodyTruncated bool   `json:"response_body_truncated"`
+}
+
+func newEchoEngine(handlers ...app.HandlerFunc) *route.Engine {
+	engine := newTestEngine(handlers...)
+	engine.POST("/echo", func(ctx context.Context, c *app.RequestContext) {
+		c.Data(200, string(c.Request.Header.ContentType()), c.Request.Body())
+	})
+	return engine
+}
+
+func performEcho(engine *route.Engine, contentType, body string) {
+	ut.PerformRequest(engine, "POST", "/echo", &ut.Body{Body: bytes.NewBufferString(body), Len: len(body)},
+		ut.Header{Key: "Content-Type", Value: contentType})
+}
+
+func TestAccessLoggerBodies(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithLevel(hlog.LevelInfo))
+	engine := newEchoEngine(AccessLogger(WithAccessLogger(l), WithAccessLogFields(0),
+		WithAccessLogRequestBody(), WithAccessLogResponseBody()))
+
+	performEcho(engine, "application/json; charset=utf-8", "{\n  \"name\": \"alice\",\n  \"tags\": [\"a b\"]\n}")
+
+	assert.Equal(t, `{"level":"info","request_body":{"name":"alice","tags":["a b"]},"response_body":{"name":"alice","tags":["a b"]},"message":"request processed"}
+`, b.String())
+}
+
+func TestAccessLoggerBodyContentTypes(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithLevel(hlog.LevelInfo))
+	engine := newEchoEngine(AccessLogger(WithAccessLogger(l), WithAccessLogFields(0), WithAccessLogRequestBody()))
+
+	performEcho(engine, "application/x-www-form-urlencoded", "name=alice&age=42")
+	performEcho(engine, "text/plain", `say "hi"`)
+	performEcho(engine, "application/json", "{invalid")
+	performEcho(engine, "application/octet-stream", "\x00\x01")
+	performEcho(engine, "", "no type")
+
+	assert.Equal(t, `{"level":"info","request_body":"name=alice&age=42","message":"request processed"}
+{"level":"info","request_body":"say \"hi\"","message":"request processed"}
+{"level":"info","request_body":"{invalid","message":"request processed"}
+{"level":"info","message":"request processed"}
+{"level":"info","message":"request processed"}
+`, b.String())
+
+	b.Reset()
+	engine = newEchoEngine(AccessLogger(WithAccessLogger(l), WithAccessLogFields(0), WithAccessLogRequestBody(),
+		WithAccessLogBodyContentTypes("application/problem+json")))
+
+	performEcho(engine, "application/problem+json", `{"title": "bad"}`)
+	performEcho(engine, "application/json", `{}`)
+
+	assert.Equal(t, `{"level":"info","request_body":{"title":"bad"},"message":"request processed"}
+{"level":"info","message":"request processed"}
+`, b.String())
+}
+
+func TestAccessLoggerBodyLimit(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithLevel(hlog.LevelInfo))
+	engine := newEchoEngine(AccessLogger(WithAccessLogger(l), WithAccessLogFields(0),
+		WithAccessLogRequestBody(), WithAccessLogResponseBody(), WithAccessLogBodyLimit(8)))
+
+	performEcho(engine, "application/json", `{"name":"alice"}`)
+	performEcho(engine, "text/plain", "héllo wörld")
+
+	lines := bytes.Split(bytes.TrimSpace(b.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+
+	log := &BodyLog{}
+	assert.NoError(t, json.Unmarshal(lines[0], log))
+	assert.Equal(t, `{"name":`, log.RequestBody)
+	assert.True(t, log.RequestBodyTruncated)
+	assert.True(t, log.ResponseBodyTruncated)
+
+	log = &BodyLog{}
+	assert.NoError(t, json.Unmarshal(lines[1], log))
+	assert.Equal(t, "héllo w", log.RequestBody)
+}
+
+func TestAccessLoggerBodyRedaction(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithLevel(hlog.LevelInfo), WithRedaction())
+	engine := newEchoEngine(AccessLogger(WithAccessLogger(l), WithAccessLogFields(0), WithAccessLogRequestBody()))
+
+	performEcho(engine, "application/json", `{"user":"alice","password":"hunter2"}`)
+
+	assert.Equal(t, `{"level":"info","request_body":{"user":"alice","password":"***"},"message":"request processed"}
+`, b.String())
+}
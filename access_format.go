@@ -0,0 +1,273 @@
+package zerolog
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+const (
+	// CommonLogFormat is the Apache Common Log Format
+	CommonLogFormat = `%h %l %u %t "%r" %>s %b`
+
+	// CombinedLogFormat is the Apache Combined Log Format
+	CombinedLogFormat = `%h %l %u %t "%r" %>s %b "%{Referer}i" "%{User-Agent}i"`
+
+	// clfTimeFormat is the format of the %t directive
+	clfTimeFormat = "[02/Jan/2006:15:04:05 -0700]"
+)
+
+type (
+	// accessLogFormat renders access log lines from an Apache mod_log_config style template
+	accessLogFormat struct {
+		segments []formatSegment
+		out      io.Writer
+	}
+
+	// formatSegment appends the rendering of a literal or a directive to buf
+	formatSegment func(buf []byte, r *accessLogRecord) []byte
+
+	// accessLogRecord holds what a directive can render
+	accessLogRecord struct {
+		c       *app.RequestContext
+		start   time.Time
+		latency time.Duration
+		status  int
+	}
+)
+
+var formatBufferPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 256)
+		return &b
+	},
+}
+
+// WithAccessLogFormat writes access logs to out as lines rendered from an Apache mod_log_config style template,
+// e.g. CommonLogFormat or CombinedLogFormat, instead of logging them through the logger.
+// The supported directives are:
+//
+//	%h remote address           %l remote logname, always "-"   %u remote user from basic auth
+//	%t time the request started %r first line of the request    %s, %>s status
+//	%b response size or "-"     %B response size                %D latency in microseconds
+//	%T latency in seconds       %m method                       %U path
+//	%q query string with '?'    %H protocol                     %{Name}i request header
+//	%{Name}o response header    %% a percent sign
+//
+// Unknown directives are written as is.
+func WithAccessLogFormat(format string, out io.Writer) AccessLogOpt {
+	f := &accessLogFormat{segments: parseAccessLogFormat(format), out: out}
+	return func(opts *AccessLogOptions) {
+		opts.format = f
+	}
+}
+
+// write renders a line for the request and writes it in a single call
+func (f *accessLogFormat) write(r *accessLogRecord) {
+	bp := formatBufferPool.Get().(*[]byte)
+	buf := (*bp)[:0]
+
+	for _, segment := range f.segments {
+		buf = segment(buf, r)
+	}
+	buf = append(buf, '\n')
+
+	_, _ = f.out.Write(buf)
+
+	*bp = buf
+	formatBufferPool.Put(bp)
+}
+
+func parseAccessLogFormat(format string) []formatSegment {
+	var segments []formatSegment
+	var literal strings.Builder
+
+	flush := func() {
+		if literal.Len() > 0 {
+			segments = append(segments, literalSegment(literal.String()))
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			literal.WriteByte(format[i])
+			continue
+		}
+
+		start := i
+		i++
+
+		var arg string
+		if format[i] == '{' {
+			end := strings.IndexByte(format[i:], '}')
+			if end < 0 || i+end+1 == len(format) {
+				literal.WriteString(format[start:])
+				break
+			}
+			arg = format[i+1 : i+end]
+			i += end + 1
+		}
+		if format[i] == '>' && i+1 < len(format) {
+			i++
+		}
+
+		segment := directiveSegment(format[i], arg)
+		if segment == nil {
+			literal.WriteString(format[start : i+1])
+			continue
+		}
+
+		flush()
+		segments = append(segments, segment)
+	}
+	flush()
+
+	return segments
+}
+
+func literalSegment(s string) formatSegment {
+	return func(buf []byte, r *accessLogRecord) []byte {
+		return append(buf, s...)
+	}
+}
+
+// directiveSegment returns the segment rendering the directive, or nil when it is not supported
+func directiveSegment(directive byte, arg string) formatSegment {
+	switch directive {
+	case '%':
+		return literalSegment("%")
+	case 'h':
+		return func(buf []byte, r *accessLogRecord) []byte {
+			return appendCLFValue(buf, r.c.ClientIP())
+		}
+	case 'l':
+		return literalSegment("-")
+	case 'u':
+		return func(buf []byte, r *accessLogRecord) []byte {
+			user, _, ok := r.c.Request.BasicAuth()
+			if !ok {
+				user = ""
+			}
+			return appendCLFValue(buf, user)
+		}
+	case 't':
+		return func(buf []byte, r *accessLogRecord) []byte {
+			return r.start.AppendFormat(buf, clfTimeFormat)
+		}
+	case 'r':
+		return func(buf []byte, r *accessLogRecord) []byte {
+			buf = appendEscaped(buf, string(r.c.Method()))
+			buf = append(buf, ' ')
+			buf = appendEscaped(buf, string(r.c.Request.RequestURI()))
+			buf = append(buf, ' ')
+			return appendEscaped(buf, requestProtocol(r.c))
+		}
+	case 's':
+		return func(buf []byte, r *accessLogRecord) []byte {
+			return strconv.AppendInt(buf, int64(r.status), 10)
+		}
+	case 'b':
+		return func(buf []byte, r *accessLogRecord) []byte {
+			if n := len(r.c.Response.Body()); n > 0 {
+				return strconv.AppendInt(buf, int64(n), 10)
+			}
+			return append(buf, '-')
+		}
+	case 'B':
+		return func(buf []byte, r *accessLogRecord) []byte {
+			return strconv.AppendInt(buf, int64(len(r.c.Response.Body())), 10)
+		}
+	case 'D':
+		return func(buf []byte, r *accessLogRecord) []byte {
+			return strconv.AppendInt(buf, r.latency.Microseconds(), 10)
+		}
+	case 'T':
+		return func(buf []byte, r *accessLogRecord) []byte {
+			return strconv.AppendInt(buf, int64(r.latency/time.Second), 10)
+		}
+	case 'm':
+		return func(buf []byte, r *accessLogRecord) []byte {
+			return appendEscaped(buf, string(r.c.Method()))
+		}
+	case 'U':
+		return func(buf []byte, r *accessLogRecord) []byte {
+			return appendEscaped(buf, string(r.c.Path()))
+		}
+	case 'q':
+		return func(buf []byte, r *accessLogRecord) []byte {
+			if query := r.c.URI().QueryString(); len(query) > 0 {
+				buf = append(buf, '?')
+				return appendEscaped(buf, string(query))
+			}
+			return buf
+		}
+	case 'H':
+		return func(buf []byte, r *accessLogRecord) []byte {
+			return appendEscaped(buf, requestProtocol(r.c))
+		}
+	case 'i':
+		if arg == "" {
+			return nil
+		}
+		return func(buf []byte, r *accessLogRecord) []byte {
+			return appendCLFValue(buf, string(r.c.Request.Header.Peek(arg)))
+		}
+	case 'o':
+		if arg == "" {
+			return nil
+		}
+		return func(buf []byte, r *accessLogRecord) []byte {
+			return appendCLFValue(buf, string(r.c.Response.Header.Peek(arg)))
+		}
+	default:
+		return nil
+	}
+}
+
+// requestProtocol returns the protocol of the request, which is not recorded for every request
+func requestProtocol(c *app.RequestContext) string {
+	if protocol := c.Request.Header.GetProtocol(); protocol != "" {
+		return protocol
+	}
+
+	if c.Request.Header.IsHTTP11() {
+		return consts.HTTP11
+	}
+
+	return consts.HTTP10
+}
+
+// appendCLFValue appends s escaped, or "-" when it is empty
+func appendCLFValue(buf []byte, s string) []byte {
+	if s == "" {
+		return append(buf, '-')
+	}
+
+	return appendEscaped(buf, s)
+}
+
+// appendEscaped appends s with quotes, backslashes and non-printable bytes escaped like Apache does,
+// so that client controlled values cannot forge lines or fields
+func appendEscaped(buf []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			buf = append(buf, '\\', c)
+		case c < 0x20 || c > 0x7e:
+			buf = append(buf, '\\', 'x', hex[c>>4], hex[c&0xf])
+		default:
+			buf = append(buf, c)
+		}
+	}
+
+	return buf
+}
@@ -0,0 +1,63 @@
+package zerolog
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessLogFormatCommon(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b))
+	out := &bytes.Buffer{}
+	engine := newTestEngine(AccessLogger(WithAccessLogger(l), WithAccessLogFormat(CommonLogFormat, out)))
+
+	ut.PerformRequest(engine, "GET", "/users/42?verbose=1", nil, ut.Header{Key: "Authorization", Value: "Basic YWxpY2U6c2VjcmV0"})
+	ut.PerformRequest(engine, "POST", "/users", nil)
+
+	assert.Empty(t, b.String())
+	assert.Regexp(t, regexp.MustCompile(
+		`^0\.0\.0\.0 - alice \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "GET /users/42\?verbose=1 HTTP/1\.1" 200 7\n`+
+			`0\.0\.0\.0 - - \[[^]]+\] "POST /users HTTP/1\.1" 400 11\n$`), out.String())
+}
+
+func TestAccessLogFormatCombined(t *testing.T) {
+	out := &bytes.Buffer{}
+	engine := newTestEngine(AccessLogger(WithAccessLogFormat(CombinedLogFormat, out)))
+
+	ut.PerformRequest(engine, "GET", "/fail", nil,
+		ut.Header{Key: "Referer", Value: "https://example.com/"},
+		ut.Header{Key: "User-Agent", Value: "agent \"quoted\"\x01"})
+
+	assert.Regexp(t, regexp.MustCompile(
+		`^0\.0\.0\.0 - - \[[^]]+\] "GET /fail HTTP/1\.1" 500 6 "https://example\.com/" "agent \\"quoted\\"\\x01"\n$`), out.String())
+}
+
+func TestAccessLogFormatCustom(t *testing.T) {
+	out := &bytes.Buffer{}
+	engine := newTestEngine(
+		RequestID(WithRequestIDGenerator(func() string { return "abc" })),
+		AccessLogger(WithAccessLogFormat(`%m %U%q %H %s %B %{X-Request-Id}o %{X-Missing}i %l 100%% %Z %{Foo} %`, out),
+			WithAccessLogSkipPaths("/health")),
+	)
+
+	ut.PerformRequest(engine, "GET", "/users/1?a=b", nil)
+	ut.PerformRequest(engine, "GET", "/health", nil)
+
+	assert.Equal(t, "GET /users/1?a=b HTTP/1.1 200 6 abc - - 100% %Z %{Foo} %\n", out.String())
+}
+
+func TestAccessLogFormatLatency(t *testing.T) {
+	segments := parseAccessLogFormat("%D %T")
+	assert.Len(t, segments, 3)
+
+	var buf []byte
+	r := &accessLogRecord{latency: 2500 * 1000 * 1000}
+	for _, segment := range segments {
+		buf = segment(buf, r)
+	}
+	assert.Equal(t, "2500000 2", string(buf))
+}
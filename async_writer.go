@@ -0,0 +1,249 @@
+package zerolog
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/rs/zerolog"
+)
+
+// DropPolicy decides what an AsyncWriter does with an event when its buffer is full
+type DropPolicy int
+
+const (
+	// BlockWhenFull blocks the caller until there is room in the buffer
+	BlockWhenFull DropPolicy = iota
+	// DropNewest drops the event being written
+	DropNewest
+	// DropOldest drops the oldest buffered event to make room
+	DropOldest
+	// DropBelowLevel drops the event being written if it is below the configured level and blocks otherwise
+	DropBelowLevel
+)
+
+// ErrWriterClosed is returned when writing to a closed AsyncWriter
+var ErrWriterClosed = errors.New("zerolog: write to closed async writer")
+
+var _ zerolog.LevelWriter = (*AsyncWriter)(nil)
+
+type (
+	AsyncWriterOptions struct {
+		size         int
+		policy       DropPolicy
+		level        zerolog.Level
+		errorHandler func(err error)
+	}
+
+	AsyncWriterOpt func(opts *AsyncWriterOptions)
+
+	asyncEntry struct {
+		level zerolog.Level
+		p     []byte
+	}
+)
+
+// AsyncWriter queues encoded events in a bounded ring buffer and writes them to the wrapped writer
+// from a background goroutine, so that logging does not block on a slow output
+type AsyncWriter struct {
+	out  io.Writer
+	opts *AsyncWriterOptions
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	idle     *sync.Cond
+	buf      []asyncEntry
+	head     int
+	count    int
+	writing  bool
+	closed   bool
+	done     chan struct{}
+
+	dropped atomic.Uint64
+}
+
+func newAsyncWriterOptions(options []AsyncWriterOpt) *AsyncWriterOptions {
+	opts := &AsyncWriterOptions{
+		size:   1024,
+		policy: BlockWhenFull,
+		level:  zerolog.WarnLevel,
+	}
+
+	for _, set := range options {
+		set(opts)
+	}
+
+	return opts
+}
+
+// WithAsyncBufferSize allows to specify the number of events the buffer holds. By default, it is set to 1024.
+func WithAsyncBufferSize(size int) AsyncWriterOpt {
+	return func(opts *AsyncWriterOptions) {
+		if size > 0 {
+			opts.size = size
+		}
+	}
+}
+
+// WithAsyncDropPolicy allows to specify what happens when the buffer is full. By default, it is set to BlockWhenFull.
+func WithAsyncDropPolicy(policy DropPolicy) AsyncWriterOpt {
+	return func(opts *AsyncWriterOptions) {
+		opts.policy = policy
+	}
+}
+
+// WithAsyncDropBelowLevel drops events below level when the buffer is full and blocks for the others
+func WithAsyncDropBelowLevel(level hlog.Level) AsyncWriterOpt {
+	lvl := matchHlogLevel(level)
+	return func(opts *AsyncWriterOptions) {
+		opts.policy = DropBelowLevel
+		opts.level = lvl
+	}
+}
+
+// WithAsyncErrorHandler allows to specify a function called when writing to the wrapped writer fails.
+// By default, errors are ignored.
+func WithAsyncErrorHandler(handler func(err error)) AsyncWriterOpt {
+	return func(opts *AsyncWriterOptions) {
+		opts.errorHandler = handler
+	}
+}
+
+// NewAsyncWriter returns an AsyncWriter writing to out. Close must be called to flush buffered events on shutdown.
+func NewAsyncWriter(out io.Writer, options ...AsyncWriterOpt) *AsyncWriter {
+	opts := newAsyncWriterOptions(options)
+
+	w := &AsyncWriter{
+		out:  out,
+		opts: opts,
+		buf:  make([]asyncEntry, opts.size),
+		done: make(chan struct{}),
+	}
+	w.notEmpty = sync.NewCond(&w.mu)
+	w.notFull = sync.NewCond(&w.mu)
+	w.idle = sync.NewCond(&w.mu)
+
+	go w.run()
+
+	return w
+}
+
+// Write queues p for writing
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel queues p, logged at level, for writing
+func (w *AsyncWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for !w.closed && w.count == len(w.buf) {
+		switch {
+		case w.opts.policy == DropNewest,
+			w.opts.policy == DropBelowLevel && level < w.opts.level:
+			w.dropped.Add(1)
+			return len(p), nil
+		case w.opts.policy == DropOldest:
+			w.buf[w.head] = asyncEntry{}
+			w.head = (w.head + 1) % len(w.buf)
+			w.count--
+			w.dropped.Add(1)
+		default:
+			w.notFull.Wait()
+		}
+	}
+
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+
+	w.buf[(w.head+w.count)%len(w.buf)] = asyncEntry{level: level, p: append([]byte(nil), p...)}
+	w.count++
+	w.notEmpty.Signal()
+
+	return len(p), nil
+}
+
+// Dropped returns the number of events dropped because the buffer was full
+func (w *AsyncWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Flush blocks until all buffered events have been written
+func (w *AsyncWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for w.count > 0 || w.writing {
+		w.idle.Wait()
+	}
+
+	return nil
+}
+
+// Close writes the buffered events and stops the background goroutine. It does not close the wrapped writer.
+func (w *AsyncWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.notEmpty.Broadcast()
+	w.notFull.Broadcast()
+	w.mu.Unlock()
+
+	<-w.done
+
+	return nil
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for {
+		for w.count == 0 && !w.closed {
+			w.notEmpty.Wait()
+		}
+		if w.count == 0 {
+			w.idle.Broadcast()
+			return
+		}
+
+		entry := w.buf[w.head]
+		w.buf[w.head] = asyncEntry{}
+		w.head = (w.head + 1) % len(w.buf)
+		w.count--
+		w.writing = true
+		w.notFull.Signal()
+		w.mu.Unlock()
+
+		w.write(entry)
+
+		w.mu.Lock()
+		w.writing = false
+		if w.count == 0 {
+			w.idle.Broadcast()
+		}
+	}
+}
+
+func (w *AsyncWriter) write(entry asyncEntry) {
+	var err error
+	if lw, ok := w.out.(zerolog.LevelWriter); ok && entry.level != zerolog.NoLevel {
+		_, err = lw.WriteLevel(entry.level, entry.p)
+	} else {
+		_, err = w.out.Write(entry.p)
+	}
+
+	if err != nil && w.opts.errorHandler != nil {
+		w.opts.errorHandler(err)
+	}
+}
@@ -0,0 +1,204 @@
+package zerolog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// gatedWriter blocks writes until the gate is opened
+type gatedWriter struct {
+	gate    chan struct{}
+	started chan struct{}
+	once    sync.Once
+	mu      sync.Mutex
+	b       bytes.Buffer
+}
+
+func newGatedWriter() *gatedWriter {
+	return &gatedWriter{gate: make(chan struct{}), started: make(chan struct{})}
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.started) })
+	<-w.gate
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.b.Write(p)
+}
+
+func (w *gatedWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.b.String()
+}
+
+func TestAsyncWriter(t *testing.T) {
+	b := &syncBuffer{}
+	w := NewAsyncWriter(b)
+	l := New(WithOutput(w))
+
+	for i := 0; i < 100; i++ {
+		l.Infof("foo %d", i)
+	}
+
+	assert.NoError(t, w.Flush())
+	assert.Equal(t, 100, strings.Count(b.String(), "\n"))
+	assert.True(t, strings.HasPrefix(b.String(), `{"level":"info","message":"foo 0"}`))
+	assert.NoError(t, w.Close())
+	assert.Equal(t, uint64(0), w.Dropped())
+}
+
+func TestAsyncWriterDropNewest(t *testing.T) {
+	out := newGatedWriter()
+	w := NewAsyncWriter(out, WithAsyncBufferSize(2), WithAsyncDropPolicy(DropNewest))
+	l := New(WithOutput(w))
+
+	l.Info("0")
+	<-out.started
+	l.Info("1")
+	l.Info("2")
+	l.Info("3")
+	l.Info("4")
+
+	close(out.gate)
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, uint64(2), w.Dropped())
+	assert.Equal(t, `{"level":"info","message":"0"}
+{"level":"info","message":"1"}
+{"level":"info","message":"2"}
+`, out.String())
+}
+
+func TestAsyncWriterDropOldest(t *testing.T) {
+	out := newGatedWriter()
+	w := NewAsyncWriter(out, WithAsyncBufferSize(2), WithAsyncDropPolicy(DropOldest))
+	l := New(WithOutput(w))
+
+	l.Info("0")
+	<-out.started
+	l.Info("1")
+	l.Info("2")
+	l.Info("3")
+	l.Info("4")
+
+	close(out.gate)
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, uint64(2), w.Dropped())
+	assert.Equal(t, `{"level":"info","message":"0"}
+{"level":"info","message":"3"}
+{"level":"info","message":"4"}
+`, out.String())
+}
+
+func TestAsyncWriterDropBelowLevel(t *testing.T) {
+	out := newGatedWriter()
+	w := NewAsyncWriter(out, WithAsyncBufferSize(1), WithAsyncDropBelowLevel(hlog.LevelWarn))
+	l := New(WithOutput(w))
+
+	l.Info("0")
+	<-out.started
+	l.Info("1")
+	l.Info("2")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		l.Error("3")
+	}()
+
+	close(out.gate)
+	<-done
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, uint64(1), w.Dropped())
+	assert.Equal(t, `{"level":"info","message":"0"}
+{"level":"info","message":"1"}
+{"level":"error","message":"3"}
+`, out.String())
+}
+
+func TestAsyncWriterBlock(t *testing.T) {
+	out := newGatedWriter()
+	w := NewAsyncWriter(out, WithAsyncBufferSize(1))
+	l := New(WithOutput(w))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			l.Info("foo")
+		}
+	}()
+
+	<-out.started
+	close(out.gate)
+	<-done
+	assert.NoError(t, w.Flush())
+
+	assert.Equal(t, uint64(0), w.Dropped())
+	assert.Equal(t, 10, strings.Count(out.String(), "\n"))
+}
+
+func TestAsyncWriterClosed(t *testing.T) {
+	w := NewAsyncWriter(&bytes.Buffer{})
+
+	assert.NoError(t, w.Close())
+	assert.NoError(t, w.Close())
+
+	_, err := w.Write([]byte("foo"))
+	assert.ErrorIs(t, err, ErrWriterClosed)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+type levelRecorder struct {
+	levels []zerolog.Level
+}
+
+func (r *levelRecorder) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (r *levelRecorder) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	r.levels = append(r.levels, level)
+	return len(p), nil
+}
+
+func TestAsyncWriterErrorHandler(t *testing.T) {
+	var errs []error
+	w := NewAsyncWriter(failingWriter{}, WithAsyncErrorHandler(func(err error) {
+		errs = append(errs, err)
+	}))
+
+	New(WithOutput(w)).Error("foo")
+	assert.NoError(t, w.Close())
+
+	assert.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "broken pipe")
+}
+
+func TestAsyncWriterLevelWriter(t *testing.T) {
+	r := &levelRecorder{}
+	w := NewAsyncWriter(r)
+	l := New(WithOutput(w))
+
+	l.Info("foo")
+	l.Error("bar")
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []zerolog.Level{zerolog.InfoLevel, zerolog.ErrorLevel}, r.levels)
+}
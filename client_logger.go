@@ -0,0 +1,193 @@
+package zerolog
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app/client"
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/cloudwego/hertz/pkg/protocol"
+	protocolClient "github.com/cloudwego/hertz/pkg/protocol/client"
+	"github.com/rs/zerolog"
+)
+
+// retryCounters maps requests in flight through ClientLogger to their retry counter, incremented by CountRetries
+var retryCounters sync.Map
+
+type (
+	ClientLogOptions struct {
+		logger          *Logger
+		message         string
+		levelFunc       func(status int) hlog.Level
+		redactor        *Redactor
+		requestIDHeader string
+	}
+
+	ClientLogOpt func(opts *ClientLogOptions)
+)
+
+func newClientLogOptions(options []ClientLogOpt) *ClientLogOptions {
+	opts := &ClientLogOptions{
+		message:         "outbound request",
+		levelFunc:       StatusLevels(hlog.LevelInfo, hlog.LevelWarn, hlog.LevelError),
+		redactor:        NewRedactor(),
+		requestIDHeader: RequestIDHeader,
+	}
+
+	for _, set := range options {
+		set(opts)
+	}
+
+	return opts
+}
+
+// WithClientLogger allows to specify the logger used when the context carries none.
+// By default, the logger returned by GetLogger at request time is used.
+func WithClientLogger(logger *Logger) ClientLogOpt {
+	return func(opts *ClientLogOptions) {
+		opts.logger = logger
+	}
+}
+
+// WithClientLogMessage allows to specify the message of outbound request logs. By default, it is set to "outbound request".
+func WithClientLogMessage(message string) ClientLogOpt {
+	return func(opts *ClientLogOptions) {
+		opts.message = message
+	}
+}
+
+// WithClientLogLevels allows to specify the level used for each status class:
+// 1xx-3xx use success, 4xx use clientError and 5xx use serverError. Requests failing with an error are logged at error level.
+func WithClientLogLevels(success, clientError, serverError hlog.Level) ClientLogOpt {
+	return func(opts *ClientLogOptions) {
+		opts.levelFunc = StatusLevels(success, clientError, serverError)
+	}
+}
+
+// WithClientQueryRedactor allows to specify the redactor masking query parameters of logged URLs.
+// By default, parameters named like DefaultRedactKeys are masked.
+func WithClientQueryRedactor(redactor *Redactor) ClientLogOpt {
+	return func(opts *ClientLogOptions) {
+		opts.redactor = redactor
+	}
+}
+
+// WithClientRequestIDHeader allows to specify the header the request id of the context is propagated in.
+// By default, it is set to X-Request-Id; an empty header disables propagation.
+func WithClientRequestIDHeader(header string) ClientLogOpt {
+	return func(opts *ClientLogOptions) {
+		opts.requestIDHeader = header
+	}
+}
+
+// ClientLogger returns a Hertz client middleware that logs outbound requests through the context logger
+// with their method, URL, status, latency, retries and error, and propagates the request id of the context.
+// Retries are only counted when the client retry function is wrapped with CountRetries.
+func ClientLogger(options ...ClientLogOpt) client.Middleware {
+	opts := newClientLogOptions(options)
+
+	return func(next client.Endpoint) client.Endpoint {
+		return func(ctx context.Context, req *protocol.Request, resp *protocol.Response) error {
+			if opts.requestIDHeader != "" && len(req.Header.Peek(opts.requestIDHeader)) == 0 {
+				if id, ok := RequestIDFromContext(ctx); ok {
+					req.Header.Set(opts.requestIDHeader, id)
+				}
+			}
+
+			retries := new(int32)
+			retryCounters.Store(req, retries)
+			defer retryCounters.Delete(req)
+
+			start := time.Now()
+			err := next(ctx, req, resp)
+			latency := time.Since(start)
+
+			opts.log(ctx, req, resp, err, latency, int(atomic.LoadInt32(retries)))
+
+			return err
+		}
+	}
+}
+
+// CountRetries wraps a client retry function so that ClientLogger reports the number of retries of requests.
+// A nil retryIf is replaced with the default retry function of the Hertz client.
+func CountRetries(retryIf protocolClient.RetryIfFunc) protocolClient.RetryIfFunc {
+	if retryIf == nil {
+		retryIf = protocolClient.DefaultRetryIf
+	}
+
+	return func(req *protocol.Request, resp *protocol.Response, err error) bool {
+		retry := retryIf(req, resp, err)
+		if retry {
+			if counter, ok := retryCounters.Load(req); ok {
+				atomic.AddInt32(counter.(*int32), 1)
+			}
+		}
+		return retry
+	}
+}
+
+func (opts *ClientLogOptions) log(ctx context.Context, req *protocol.Request, resp *protocol.Response, err error,
+	latency time.Duration, retries int,
+) {
+	base := opts.logger
+	if base == nil {
+		base = GetLogger()
+	}
+	if base == nil {
+		base = From(*zerolog.Ctx(ctx))
+	}
+
+	level := hlog.LevelError
+	if err == nil {
+		level = opts.levelFunc(resp.StatusCode())
+	}
+
+	e := base.ctxLogger(ctx).current().WithLevel(matchHlogLevel(level))
+	if e == nil {
+		return
+	}
+	e = base.traceFields(e, ctx)
+
+	e.Bytes("method", req.Method()).
+		Str("url", opts.redactURL(req.URI().String())).
+		Dur("latency", latency)
+	if err != nil {
+		e.Err(err)
+	} else {
+		e.Int("status", resp.StatusCode())
+	}
+	if retries > 0 {
+		e.Int("retries", retries)
+	}
+
+	e.Msg(opts.message)
+}
+
+// redactURL drops the fragment of rawURL and masks the values of redacted query parameters,
+// keeping the order and encoding of the others
+func (opts *ClientLogOptions) redactURL(rawURL string) string {
+	if i := strings.IndexByte(rawURL, '#'); i >= 0 {
+		rawURL = rawURL[:i]
+	}
+
+	i := strings.IndexByte(rawURL, '?')
+	if i < 0 || opts.redactor == nil {
+		return rawURL
+	}
+
+	params := strings.Split(rawURL[i+1:], "&")
+	for j, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if name, err := url.QueryUnescape(key); err == nil && opts.redactor.Match(name) {
+			value, _ := url.QueryUnescape(strings.TrimPrefix(param[len(key):], "="))
+			params[j] = key + "=" + opts.redactor.Mask(value)
+		}
+	}
+
+	return rawURL[:i+1] + strings.Join(params, "&")
+}
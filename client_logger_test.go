@@ -0,0 +1,135 @@
+package zerolog
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app/client"
+	"github.com/cloudwego/hertz/pkg/app/client/retry"
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/cloudwego/hertz/pkg/common/json"
+	"github.com/cloudwego/hertz/pkg/protocol"
+	"github.com/stretchr/testify/assert"
+)
+
+type ClientLog struct {
+	Level     string   `json:"level"`
+	RequestID string   `json:"request_id"`
+	Method    string   `json:"method"`
+	URL       string   `json:"url"`
+	Status    int      `json:"status"`
+	Latency   *float64 `json:"latency"`
+	Retries   int      `json:"retries"`
+	Error     string   `json:"error"`
+	Message   string   `json:"message"`
+}
+
+func newTestClient(t *testing.T, mws ...client.Middleware) *client.Client {
+	c, err := client.NewClient(client.WithDialTimeout(time.Second))
+	assert.NoError(t, err)
+	c.Use(mws...)
+	return c
+}
+
+func TestClientLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(r.Header.Get(RequestIDHeader)))
+	}))
+	defer server.Close()
+
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b))
+	c := newTestClient(t, ClientLogger(WithClientLogger(l)))
+
+	ctx := context.WithValue(context.Background(), requestIDCtxKey{}, "abc")
+	ctx = l.WithField(RequestIDKey, "abc").WithContext(ctx)
+
+	status, body, err := c.Get(ctx, nil, server.URL+"/users?id=1&token=s%20cret&Api_Key=k#frag")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "abc", string(body))
+
+	log := &ClientLog{}
+	assert.NoError(t, json.Unmarshal(b.Bytes(), log))
+	assert.Equal(t, "warn", log.Level)
+	assert.Equal(t, "abc", log.RequestID)
+	assert.Equal(t, "GET", log.Method)
+	assert.Equal(t, server.URL+"/users?id=1&token=***&Api_Key=***", log.URL)
+	assert.Equal(t, http.StatusNotFound, log.Status)
+	assert.NotNil(t, log.Latency)
+	assert.Equal(t, 0, log.Retries)
+	assert.Equal(t, "outbound request", log.Message)
+}
+
+func TestClientLoggerOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get(RequestIDHeader)))
+	}))
+	defer server.Close()
+
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b))
+	c := newTestClient(t, ClientLogger(
+		WithClientLogger(l),
+		WithClientLogMessage("call"),
+		WithClientLogLevels(hlog.LevelDebug, hlog.LevelWarn, hlog.LevelError),
+		WithClientQueryRedactor(NewRedactor(WithRedactKeys("id"), WithRedactMasker(MaskPartial(0, 1)))),
+		WithClientRequestIDHeader(""),
+	))
+
+	ctx := context.WithValue(context.Background(), requestIDCtxKey{}, "abc")
+	_, body, err := c.Get(ctx, nil, server.URL+"/users?id=123")
+	assert.NoError(t, err)
+	assert.Empty(t, body)
+
+	log := &ClientLog{}
+	assert.NoError(t, json.Unmarshal(b.Bytes(), log))
+	assert.Equal(t, "debug", log.Level)
+	assert.Equal(t, server.URL+"/users?id=**3", log.URL)
+	assert.Equal(t, "call", log.Message)
+}
+
+func TestClientLoggerRetries(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := listener.Addr().String()
+	assert.NoError(t, listener.Close())
+
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b))
+	c, err := client.NewClient(
+		client.WithDialTimeout(time.Second),
+		client.WithRetryConfig(retry.WithMaxAttemptTimes(3), retry.WithInitDelay(time.Millisecond)),
+	)
+	assert.NoError(t, err)
+	c.SetRetryIfFunc(CountRetries(func(req *protocol.Request, resp *protocol.Response, err error) bool {
+		return true
+	}))
+	c.Use(ClientLogger(WithClientLogger(l)))
+
+	_, _, err = c.Get(context.Background(), nil, "http://"+addr+"/")
+	assert.Error(t, err)
+
+	log := &ClientLog{}
+	assert.NoError(t, json.Unmarshal(b.Bytes(), log))
+	assert.Equal(t, "error", log.Level)
+	assert.Equal(t, 2, log.Retries)
+	assert.NotEmpty(t, log.Error)
+	assert.Equal(t, 0, log.Status)
+}
+
+func TestCountRetriesUntracked(t *testing.T) {
+	retryIf := CountRetries(nil)
+
+	req := protocol.AcquireRequest()
+	defer protocol.ReleaseRequest(req)
+	req.SetMethod("GET")
+
+	assert.True(t, retryIf(req, nil, nil))
+}
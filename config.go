@@ -0,0 +1,297 @@
+package zerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables read by LoadConfig, overriding the values of the config file
+const (
+	EnvLogLevel      = "LOG_LEVEL"
+	EnvLogFormat     = "LOG_FORMAT"
+	EnvLogOutput     = "LOG_OUTPUT"
+	EnvLogCaller     = "LOG_CALLER"
+	EnvLogTimeFormat = "LOG_TIME_FORMAT"
+)
+
+// Formats of Config
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// Outputs of Config other than file paths
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+)
+
+// timeFormatAliases are the names accepted as Config.TimeFormat besides Go time layouts
+var timeFormatAliases = map[string]string{
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"unix":        zerolog.TimeFormatUnix,
+	"unixms":      zerolog.TimeFormatUnixMs,
+	"unixmicro":   zerolog.TimeFormatUnixMicro,
+}
+
+// Config describes a Logger. The zero value describes a logger writing JSON to os.Stdout.
+type Config struct {
+	// Level is an hlog level name such as debug or notice. By default, the level is not changed.
+	Level string `json:"level" yaml:"level"`
+	// Format is FormatJSON or FormatConsole. By default, it is FormatJSON.
+	Format string `json:"format" yaml:"format"`
+	// Output is OutputStdout, OutputStderr or the path of a file to append to. By default, it is OutputStdout.
+	Output string `json:"output" yaml:"output"`
+	// Caller adds the caller to logs
+	Caller bool `json:"caller" yaml:"caller"`
+	// TimeFormat adds a timestamp to logs, formatted with a Go time layout or one of rfc3339, rfc3339nano, unix, unixms
+	// and unixmicro. By default, logs have no timestamp.
+	TimeFormat string `json:"time_format" yaml:"time_format"`
+	// Fields are added to every log
+	Fields map[string]interface{} `json:"fields" yaml:"fields"`
+	// Sampling drops part of the trace, debug and info logs. By default, logs are not sampled.
+	Sampling SamplingConfig `json:"sampling" yaml:"sampling"`
+	// Redaction masks sensitive values before logs are written. By default, logs are not redacted.
+	Redaction *RedactionConfig `json:"redaction" yaml:"redaction"`
+}
+
+// SamplingConfig describes the sampling of trace, debug and info logs, see WithEveryNSampling and WithBurstSampling
+type SamplingConfig struct {
+	// EveryN keeps one in every n logs
+	EveryN uint32 `json:"every_n" yaml:"every_n"`
+	// Burst keeps burst logs per period
+	Burst uint32 `json:"burst" yaml:"burst"`
+	// Period is the period of Burst, formatted like 1s or 500ms
+	Period string `json:"period" yaml:"period"`
+}
+
+// RedactionConfig describes the redaction of logs, see WithRedaction. DefaultRedactKeys are always redacted.
+type RedactionConfig struct {
+	// Keys are the names of fields redacted in addition to DefaultRedactKeys
+	Keys []string `json:"keys" yaml:"keys"`
+	// Paths are dot separated glob patterns of redacted fields
+	Paths []string `json:"paths" yaml:"paths"`
+	// PII masks personal data found by DefaultPIIDetectors
+	PII bool `json:"pii" yaml:"pii"`
+}
+
+// LoadConfig reads the config from the JSON or YAML file at path, chosen by its extension, then overrides it
+// with the LOG_LEVEL, LOG_FORMAT, LOG_OUTPUT, LOG_CALLER and LOG_TIME_FORMAT environment variables.
+// The file is skipped when path is empty. Unknown keys in the file are rejected.
+func LoadConfig(path string) (Config, error) {
+	var data []byte
+
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return Config{}, err
+		}
+	}
+
+	return loadConfig(path, data)
+}
+
+// loadConfig parses the content of the file at path, then applies the environment and validates the result
+func loadConfig(path string, data []byte) (Config, error) {
+	var cfg Config
+
+	if path != "" {
+		var err error
+		if cfg, err = parseConfig(filepath.Ext(path), data); err != nil {
+			return Config{}, fmt.Errorf("invalid log config %s: %w", path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+// NewFromConfig returns a new Logger described by cfg, with the options applied on top
+func NewFromConfig(cfg Config, options ...Opt) (*Logger, error) {
+	opts, err := cfg.Options()
+	if err != nil {
+		return nil, err
+	}
+
+	return New(append(opts, options...)...), nil
+}
+
+// Validate reports the first invalid value of the config
+func (c Config) Validate() error {
+	if c.Level != "" {
+		if _, err := ParseLevel(c.Level); err != nil {
+			return err
+		}
+	}
+
+	switch strings.ToLower(c.Format) {
+	case "", FormatJSON, FormatConsole:
+	default:
+		return fmt.Errorf("unknown log format %q", c.Format)
+	}
+
+	if c.Sampling.EveryN > 0 && c.Sampling.Burst > 0 {
+		return errors.New("sampling every_n and burst cannot be combined")
+	}
+	if c.Sampling.Burst > 0 {
+		if period, err := time.ParseDuration(c.Sampling.Period); err != nil || period <= 0 {
+			return fmt.Errorf("invalid sampling period %q", c.Sampling.Period)
+		}
+	}
+
+	return nil
+}
+
+// Options returns the options building a Logger described by the config.
+// A file output is opened, so an error is returned if it cannot be.
+func (c Config) Options() ([]Opt, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	out, err := c.output()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.options(out), nil
+}
+
+// options returns the options building a Logger described by the config that writes to out
+func (c Config) options(out io.Writer) []Opt {
+	timeFormat, timestamp := c.timeFormat()
+	if strings.EqualFold(c.Format, FormatConsole) {
+		console := zerolog.ConsoleWriter{Out: out, NoColor: true}
+		if timestamp {
+			console.TimeFormat = timeFormat
+		}
+		out = console
+	}
+
+	opts := []Opt{WithOutput(out)}
+	if c.Level != "" {
+		level, _ := ParseLevel(c.Level)
+		opts = append(opts, WithLevel(level))
+	}
+	if len(c.Fields) > 0 {
+		opts = append(opts, WithFields(c.Fields))
+	}
+	if timestamp {
+		opts = append(opts, WithFormattedTimestamp(timeFormat))
+	}
+	if c.Caller {
+		opts = append(opts, WithCaller())
+	}
+	if c.Sampling.EveryN > 0 {
+		opts = append(opts, WithEveryNSampling(c.Sampling.EveryN))
+	}
+	if c.Sampling.Burst > 0 {
+		period, _ := time.ParseDuration(c.Sampling.Period)
+		opts = append(opts, WithBurstSampling(c.Sampling.Burst, period))
+	}
+	if c.Redaction != nil {
+		opts = append(opts, WithRedaction(c.Redaction.options()...))
+	}
+
+	return opts
+}
+
+func (c *RedactionConfig) options() []RedactOpt {
+	opts := []RedactOpt{WithRedactKeys(c.Keys...), WithRedactPaths(c.Paths...)}
+	if c.PII {
+		opts = append(opts, WithPIIDetectors(DefaultPIIDetectors()...))
+	}
+
+	return opts
+}
+
+func (c Config) output() (io.Writer, error) {
+	switch c.Output {
+	case "", OutputStdout:
+		return os.Stdout, nil
+	case OutputStderr:
+		return os.Stderr, nil
+	default:
+		return NewFileWriter(c.Output)
+	}
+}
+
+// timeFormat returns the time layout of the config and whether logs have a timestamp
+func (c Config) timeFormat() (string, bool) {
+	if c.TimeFormat == "" {
+		return "", false
+	}
+
+	if format, ok := timeFormatAliases[strings.ToLower(c.TimeFormat)]; ok {
+		return format, true
+	}
+
+	return c.TimeFormat, true
+}
+
+func (c *Config) applyEnv() error {
+	if level, ok := os.LookupEnv(EnvLogLevel); ok {
+		c.Level = level
+	}
+	if format, ok := os.LookupEnv(EnvLogFormat); ok {
+		c.Format = format
+	}
+	if output, ok := os.LookupEnv(EnvLogOutput); ok {
+		c.Output = output
+	}
+	if caller, ok := os.LookupEnv(EnvLogCaller); ok {
+		enabled, err := strconv.ParseBool(caller)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", EnvLogCaller, caller)
+		}
+		c.Caller = enabled
+	}
+	if timeFormat, ok := os.LookupEnv(EnvLogTimeFormat); ok {
+		c.TimeFormat = timeFormat
+	}
+
+	return nil
+}
+
+// parseConfig decodes data as JSON or YAML according to the file extension
+func parseConfig(ext string, data []byte) (Config, error) {
+	var cfg Config
+
+	switch strings.ToLower(ext) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&cfg); err != nil {
+			return Config{}, err
+		}
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&cfg); err != nil && err != io.EOF {
+			return Config{}, err
+		}
+	default:
+		return Config{}, fmt.Errorf("unsupported file extension %q", ext)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,173 @@
+package zerolog
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoadConfigJSON(t *testing.T) {
+	path := writeConfig(t, "log.json", `{"level":"debug","format":"console","output":"stderr","caller":true,"time_format":"rfc3339","fields":{"service":"api"}}`)
+
+	cfg, err := LoadConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, Config{
+		Level:      "debug",
+		Format:     FormatConsole,
+		Output:     OutputStderr,
+		Caller:     true,
+		TimeFormat: "rfc3339",
+		Fields:     map[string]interface{}{"service": "api"},
+	}, cfg)
+}
+
+func TestLoadConfigYAML(t *testing.T) {
+	path := writeConfig(t, "log.yml", "level: info\ntime_format: unixms\nfields:\n  service: api\n  replicas: 3\n")
+
+	cfg, err := LoadConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, Config{
+		Level:      "info",
+		TimeFormat: "unixms",
+		Fields:     map[string]interface{}{"service": "api", "replicas": 3},
+	}, cfg)
+
+	cfg, err = LoadConfig(writeConfig(t, "empty.yaml", ""))
+	assert.NoError(t, err)
+	assert.Equal(t, Config{}, cfg)
+}
+
+func TestLoadConfigEnv(t *testing.T) {
+	path := writeConfig(t, "log.json", `{"level":"debug","output":"stderr"}`)
+	t.Setenv(EnvLogLevel, "WARNING")
+	t.Setenv(EnvLogFormat, "json")
+	t.Setenv(EnvLogCaller, "true")
+	t.Setenv(EnvLogTimeFormat, "2006-01-02")
+
+	cfg, err := LoadConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, Config{
+		Level:      "WARNING",
+		Format:     FormatJSON,
+		Output:     OutputStderr,
+		Caller:     true,
+		TimeFormat: "2006-01-02",
+	}, cfg)
+
+	t.Setenv(EnvLogOutput, "stdout")
+	cfg, err = LoadConfig("")
+	assert.NoError(t, err)
+	assert.Equal(t, OutputStdout, cfg.Output)
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	_, err := LoadConfig(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	path := writeConfig(t, "log.toml", "")
+	_, err = LoadConfig(path)
+	assert.EqualError(t, err, `invalid log config `+path+`: unsupported file extension ".toml"`)
+
+	_, err = LoadConfig(writeConfig(t, "log.json", `{"levle":"debug"}`))
+	assert.ErrorContains(t, err, `unknown field "levle"`)
+
+	_, err = LoadConfig(writeConfig(t, "log.yaml", "levle: debug\n"))
+	assert.ErrorContains(t, err, "field levle not found")
+
+	_, err = LoadConfig(writeConfig(t, "log.json", `{"level":"verbose"}`))
+	assert.EqualError(t, err, `unknown log level "verbose"`)
+
+	_, err = LoadConfig(writeConfig(t, "log.json", `{"format":"xml"}`))
+	assert.EqualError(t, err, `unknown log format "xml"`)
+
+	t.Setenv(EnvLogCaller, "maybe")
+	_, err = LoadConfig("")
+	assert.EqualError(t, err, `invalid LOG_CALLER "maybe"`)
+}
+
+func TestNewFromConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	l, err := NewFromConfig(Config{Level: "info", Output: path, Fields: map[string]interface{}{"service": "api"}})
+	assert.NoError(t, err)
+	assert.Equal(t, hlog.LevelInfo, l.GetLevel())
+
+	l.Debug("dropped")
+	l.Info("foo")
+
+	assert.Equal(t, `{"level":"info","service":"api","message":"foo"}
+`, readFile(t, path))
+}
+
+func TestNewFromConfigConsole(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	l, err := NewFromConfig(Config{Format: "console", Output: path}, WithField("k", "v"))
+	assert.NoError(t, err)
+
+	l.Info("foo")
+
+	assert.Equal(t, "<nil> INF foo k=v\n", readFile(t, path))
+}
+
+func TestNewFromConfigTimestampAndCaller(t *testing.T) {
+	defer func(format string) { zerolog.TimeFieldFormat = format }(zerolog.TimeFieldFormat)
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	l, err := NewFromConfig(Config{Output: path, TimeFormat: "unix", Caller: true})
+	assert.NoError(t, err)
+
+	l.Info("foo")
+
+	line := readFile(t, path)
+	assert.Regexp(t, `^\{"level":"info","time":\d+,"caller":"[^"]+","message":"foo"\}\n$`, line)
+}
+
+func TestNewFromConfigErrors(t *testing.T) {
+	_, err := NewFromConfig(Config{Level: "loud"})
+	assert.EqualError(t, err, `unknown log level "loud"`)
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "file"), nil, 0o644))
+	_, err = NewFromConfig(Config{Output: filepath.Join(dir, "file", "app.log")})
+	assert.Error(t, err)
+	assert.False(t, strings.Contains(err.Error(), "unknown"))
+}
+
+func TestConfigSamplingAndRedaction(t *testing.T) {
+	path := writeConfig(t, "log.yaml", "sampling:\n  burst: 2\n  period: 1h\nredaction:\n  keys: [card]\n  paths: [user.email]\n  pii: true\n")
+
+	cfg, err := LoadConfig(path)
+	assert.NoError(t, err)
+	assert.Equal(t, SamplingConfig{Burst: 2, Period: "1h"}, cfg.Sampling)
+	assert.Equal(t, &RedactionConfig{Keys: []string{"card"}, Paths: []string{"user.email"}, PII: true}, cfg.Redaction)
+
+	b := &bytes.Buffer{}
+	l, err := NewFromConfig(cfg, WithOutput(b))
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		l.Infow("paid", "card", "4111", "user", map[string]string{"email": "a@b.c"}, "ip", "10.0.0.1")
+	}
+	assert.Equal(t, strings.Repeat(`{"level":"info","card":"***","user":{"email":"***"},"ip":"***","message":"paid"}`+"\n", 2), b.String())
+}
+
+func TestConfigSamplingErrors(t *testing.T) {
+	assert.EqualError(t, Config{Sampling: SamplingConfig{EveryN: 2, Burst: 2, Period: "1s"}}.Validate(),
+		"sampling every_n and burst cannot be combined")
+	assert.EqualError(t, Config{Sampling: SamplingConfig{Burst: 2, Period: "-1s"}}.Validate(),
+		`invalid sampling period "-1s"`)
+	assert.NoError(t, Config{Sampling: SamplingConfig{EveryN: 2}}.Validate())
+}
@@ -0,0 +1,313 @@
+package zerolog
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// restartConfigKeys are the keys of Config whose changes are not applied by a ConfigWatcher
+var restartConfigKeys = map[string]struct{}{
+	"format":      {},
+	"output":      {},
+	"time_format": {},
+}
+
+var _ zerolog.LevelWriter = (*watchedRedactWriter)(nil)
+
+type (
+	WatchOptions struct {
+		interval time.Duration
+		options  []Opt
+		reporter *Logger
+	}
+
+	WatchOpt func(opts *WatchOptions)
+
+	// ConfigWatcher polls a config file and applies its changes to a Logger
+	ConfigWatcher struct {
+		path     string
+		opts     *WatchOptions
+		logger   *Logger
+		out      io.Writer
+		redactor atomic.Pointer[Redactor]
+
+		mu      sync.Mutex
+		cfg     Config
+		data    []byte
+		lastErr string
+
+		stop      chan struct{}
+		done      chan struct{}
+		closeOnce sync.Once
+	}
+
+	// configChange is the old and new value of a key of Config
+	configChange struct {
+		key      string
+		from, to interface{}
+	}
+
+	// watchedRedactWriter redacts events with the redactor currently configured by a ConfigWatcher, if any
+	watchedRedactWriter struct {
+		redactor *atomic.Pointer[Redactor]
+		out      io.Writer
+	}
+)
+
+func newWatchOptions(options []WatchOpt) *WatchOptions {
+	opts := &WatchOptions{
+		interval: 5 * time.Second,
+	}
+
+	for _, set := range options {
+		set(opts)
+	}
+
+	return opts
+}
+
+// WithWatchInterval allows to specify how often the config file is checked for changes. By default, it is set to 5 seconds.
+func WithWatchInterval(interval time.Duration) WatchOpt {
+	return func(opts *WatchOptions) {
+		opts.interval = interval
+	}
+}
+
+// WithWatchLoggerOptions allows to specify options applied on top of the config each time the logger is built
+func WithWatchLoggerOptions(options ...Opt) WatchOpt {
+	return func(opts *WatchOptions) {
+		opts.options = append(opts.options, options...)
+	}
+}
+
+// WithWatchReporter allows to specify the logger that reloads and rejected configs are reported through.
+// By default, the watched logger is used.
+func WithWatchReporter(logger *Logger) WatchOpt {
+	return func(opts *WatchOptions) {
+		opts.reporter = logger
+	}
+}
+
+// WatchConfig loads the config at path like LoadConfig, builds a Logger from it and polls the file for changes.
+// Changes of the level, fields, caller, sampling and redaction are applied to the logger without restarting,
+// while changes of the format, output and time format are reported and ignored. Each reload is logged with the
+// changed keys; an invalid config is reported and the previous one is kept.
+// When the level is left unchanged by a reload, a level set at runtime with SetLevel is kept.
+// Child loggers created before a reload keep the previous config.
+func WatchConfig(path string, options ...WatchOpt) (*ConfigWatcher, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := loadConfig(path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := cfg.output()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &ConfigWatcher{
+		path: path,
+		opts: newWatchOptions(options),
+		out:  out,
+		cfg:  cfg,
+		data: data,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	w.logger = New(w.loggerOptions(cfg)...)
+
+	go w.run()
+
+	return w, nil
+}
+
+// Logger returns the watched logger
+func (w *ConfigWatcher) Logger() *Logger {
+	return w.logger
+}
+
+// Config returns the config currently applied to the logger
+func (w *ConfigWatcher) Config() Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.cfg
+}
+
+// Reload reads the config file and applies its changes immediately, returning the error rejecting it if any
+func (w *ConfigWatcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	data, err := os.ReadFile(w.path)
+	if err == nil && bytes.Equal(data, w.data) {
+		return nil
+	}
+
+	var cfg Config
+	if err == nil {
+		cfg, err = loadConfig(w.path, data)
+	}
+	if err != nil {
+		if err.Error() != w.lastErr {
+			w.lastErr = err.Error()
+			w.reporter().current().Error().Err(err).Str("path", w.path).Msg("log config rejected")
+		}
+		return err
+	}
+
+	var applied, ignored []configChange
+	for _, change := range diffConfig(w.cfg, cfg) {
+		if _, ok := restartConfigKeys[change.key]; ok {
+			ignored = append(ignored, change)
+		} else {
+			applied = append(applied, change)
+		}
+	}
+	cfg.Format, cfg.Output, cfg.TimeFormat = w.cfg.Format, w.cfg.Output, w.cfg.TimeFormat
+
+	if len(applied) > 0 {
+		w.apply(cfg, cfg.Level == w.cfg.Level)
+	}
+	w.cfg, w.data, w.lastErr = cfg, data, ""
+
+	if len(ignored) > 0 {
+		w.reporter().current().Warn().Str("path", w.path).Dict("changes", changesDict(ignored)).
+			Msg("log config changes require a restart")
+	}
+	if len(applied) > 0 {
+		w.reporter().current().Info().Str("path", w.path).Dict("changes", changesDict(applied)).
+			Msg("log config reloaded")
+	}
+
+	return nil
+}
+
+// Close stops watching the config file. The output of the logger is left open.
+func (w *ConfigWatcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.stop)
+		<-w.done
+	})
+
+	return nil
+}
+
+func (w *ConfigWatcher) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.opts.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			_ = w.Reload()
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// apply rebuilds the logger from cfg, keeping its current level if keepLevel is set
+func (w *ConfigWatcher) apply(cfg Config, keepLevel bool) {
+	built := New(w.loggerOptions(cfg)...)
+	log := *built.log.Load()
+
+	w.logger.update(func(current zerolog.Logger) zerolog.Logger {
+		w.logger.out = built.out
+		if keepLevel {
+			return log.Level(current.GetLevel())
+		}
+		return log
+	})
+}
+
+// loggerOptions returns the options building the logger described by cfg.
+// Redaction is done by a stage shared by every build, so that the stages of the logger stay valid.
+func (w *ConfigWatcher) loggerOptions(cfg Config) []Opt {
+	if cfg.Redaction != nil {
+		w.redactor.Store(NewRedactor(cfg.Redaction.options()...))
+	} else {
+		w.redactor.Store(nil)
+	}
+	cfg.Redaction = nil
+
+	opts := append(cfg.options(w.out), func(opts *Options) {
+		opts.stages = append(opts.stages, unnamedStage(w.redactWriter))
+	})
+
+	return append(opts, w.opts.options...)
+}
+
+func (w *ConfigWatcher) redactWriter(out io.Writer) io.Writer {
+	return &watchedRedactWriter{redactor: &w.redactor, out: out}
+}
+
+func (w *ConfigWatcher) reporter() *Logger {
+	if w.opts.reporter != nil {
+		return w.opts.reporter
+	}
+
+	return w.logger
+}
+
+func (w *watchedRedactWriter) Write(p []byte) (int, error) {
+	if r := w.redactor.Load(); r != nil {
+		return r.Writer(w.out).Write(p)
+	}
+
+	return w.out.Write(p)
+}
+
+func (w *watchedRedactWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if r := w.redactor.Load(); r != nil {
+		return r.Writer(w.out).(zerolog.LevelWriter).WriteLevel(level, p)
+	}
+
+	if lw, ok := w.out.(zerolog.LevelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+
+	return w.out.Write(p)
+}
+
+// diffConfig returns the keys of Config whose values differ between from and to, in field order
+func diffConfig(from, to Config) []configChange {
+	var changes []configChange
+
+	fromValue, toValue := reflect.ValueOf(from), reflect.ValueOf(to)
+	for i := 0; i < fromValue.NumField(); i++ {
+		f, t := fromValue.Field(i).Interface(), toValue.Field(i).Interface()
+		if reflect.DeepEqual(f, t) {
+			continue
+		}
+
+		key, _, _ := strings.Cut(fromValue.Type().Field(i).Tag.Get("json"), ",")
+		changes = append(changes, configChange{key: key, from: f, to: t})
+	}
+
+	return changes
+}
+
+func changesDict(changes []configChange) *zerolog.Event {
+	dict := zerolog.Dict()
+	for _, change := range changes {
+		dict.Dict(change.key, zerolog.Dict().Interface("old", change.from).Interface("new", change.to))
+	}
+
+	return dict
+}
@@ -0,0 +1,163 @@
+package zerolog
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/cloudwego/hertz/pkg/common/json"
+	"github.com/stretchr/testify/assert"
+)
+
+func readLogs(t *testing.T, b *bytes.Buffer) []map[string]interface{} {
+	var logs []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		log := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &log))
+		logs = append(logs, log)
+	}
+	b.Reset()
+	return logs
+}
+
+func TestConfigWatcherReload(t *testing.T) {
+	path := writeConfig(t, "log.yaml", "level: warn\nfields:\n  service: api\n")
+	b := &bytes.Buffer{}
+
+	w, err := WatchConfig(path, WithWatchInterval(time.Hour), WithWatchLoggerOptions(WithOutput(b)))
+	assert.NoError(t, err)
+	defer w.Close()
+
+	l := w.Logger()
+	assert.Equal(t, hlog.LevelWarn, l.GetLevel())
+
+	assert.NoError(t, os.WriteFile(path, []byte("level: info\nfields:\n  service: billing\nredaction:\n  keys: [card]\n"), 0o644))
+	assert.NoError(t, w.Reload())
+	assert.Equal(t, hlog.LevelInfo, l.GetLevel())
+	assert.Equal(t, "billing", w.Config().Fields["service"])
+
+	logs := readLogs(t, b)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "log config reloaded", logs[0]["message"])
+	assert.Equal(t, path, logs[0]["path"])
+	assert.Equal(t, map[string]interface{}{
+		"level":     map[string]interface{}{"old": "warn", "new": "info"},
+		"fields":    map[string]interface{}{"old": map[string]interface{}{"service": "api"}, "new": map[string]interface{}{"service": "billing"}},
+		"redaction": map[string]interface{}{"old": nil, "new": map[string]interface{}{"keys": []interface{}{"card"}, "paths": nil, "pii": false}},
+	}, logs[0]["changes"])
+
+	l.Infow("paid", "card", "4111", "password", "secret")
+	assert.Equal(t, `{"level":"info","service":"billing","card":"***","password":"***","message":"paid"}`+"\n", b.String())
+	b.Reset()
+
+	assert.NoError(t, w.Reload())
+	assert.Empty(t, b.String())
+}
+
+func TestConfigWatcherKeepsRuntimeLevel(t *testing.T) {
+	path := writeConfig(t, "log.json", `{"level":"warn","redaction":{}}`)
+	b := &bytes.Buffer{}
+
+	w, err := WatchConfig(path, WithWatchInterval(time.Hour), WithWatchLoggerOptions(WithOutput(b)))
+	assert.NoError(t, err)
+	defer w.Close()
+
+	l := w.Logger()
+	l.SetLevel(hlog.LevelDebug)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"level":"warn","sampling":{"every_n":2}}`), 0o644))
+	assert.NoError(t, w.Reload())
+	assert.Equal(t, hlog.LevelDebug, l.GetLevel())
+	readLogs(t, b)
+
+	for i := 0; i < 4; i++ {
+		l.Debugw("tick", "token", "t")
+	}
+	assert.Len(t, readLogs(t, b), 2)
+
+	l.Warnw("tock", "token", "t")
+	assert.Equal(t, `{"level":"warn","token":"t","message":"tock"}`+"\n", b.String())
+}
+
+func TestConfigWatcherRejectsInvalidConfig(t *testing.T) {
+	path := writeConfig(t, "log.json", `{"level":"info","fields":{"service":"api"}}`)
+	b := &bytes.Buffer{}
+
+	w, err := WatchConfig(path, WithWatchInterval(time.Hour), WithWatchLoggerOptions(WithOutput(b)))
+	assert.NoError(t, err)
+	defer w.Close()
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"level":"loud","fields":{"service":"billing"}}`), 0o644))
+	assert.EqualError(t, w.Reload(), `unknown log level "loud"`)
+	assert.EqualError(t, w.Reload(), `unknown log level "loud"`)
+
+	logs := readLogs(t, b)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "error", logs[0]["level"])
+	assert.Equal(t, `unknown log level "loud"`, logs[0]["error"])
+	assert.Equal(t, "log config rejected", logs[0]["message"])
+
+	assert.Equal(t, hlog.LevelInfo, w.Logger().GetLevel())
+	assert.Equal(t, "api", w.Config().Fields["service"])
+
+	assert.NoError(t, os.Remove(path))
+	assert.ErrorIs(t, w.Reload(), os.ErrNotExist)
+	assert.Len(t, readLogs(t, b), 1)
+}
+
+func TestConfigWatcherRestartKeys(t *testing.T) {
+	path := writeConfig(t, "log.json", `{"level":"info"}`)
+	b := &bytes.Buffer{}
+	reporter := New(WithOutput(b))
+
+	w, err := WatchConfig(path, WithWatchInterval(time.Hour), WithWatchReporter(reporter),
+		WithWatchLoggerOptions(WithOutput(&bytes.Buffer{})))
+	assert.NoError(t, err)
+	defer w.Close()
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"level":"info","format":"console","output":"stderr"}`), 0o644))
+	assert.NoError(t, w.Reload())
+
+	logs := readLogs(t, b)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "warn", logs[0]["level"])
+	assert.Equal(t, "log config changes require a restart", logs[0]["message"])
+	assert.Equal(t, map[string]interface{}{
+		"format": map[string]interface{}{"old": "", "new": "console"},
+		"output": map[string]interface{}{"old": "", "new": "stderr"},
+	}, logs[0]["changes"])
+	assert.Equal(t, Config{Level: "info"}, w.Config())
+}
+
+func TestConfigWatcherPolling(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "app.log")
+	path := writeConfig(t, "log.json", `{"level":"warn","output":"`+out+`"}`)
+
+	w, err := WatchConfig(path, WithWatchInterval(10*time.Millisecond))
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(path, []byte(`{"level":"debug","output":"`+out+`"}`), 0o644))
+	assert.Eventually(t, func() bool {
+		return w.Logger().GetLevel() == hlog.LevelDebug
+	}, time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, w.Close())
+	assert.NoError(t, w.Close())
+	assert.Contains(t, readFile(t, out), `"message":"log config reloaded"`)
+}
+
+func TestWatchConfigErrors(t *testing.T) {
+	_, err := WatchConfig(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = WatchConfig(writeConfig(t, "log.json", `{"sampling":{"burst":5}}`))
+	assert.EqualError(t, err, `invalid sampling period ""`)
+}
@@ -0,0 +1,257 @@
+package zerolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Fields added to the summary of suppressed duplicates
+const (
+	RepeatedFieldName = "repeated"
+	SummaryFieldName  = "summary"
+)
+
+var _ zerolog.LevelWriter = (*dedupWriter)(nil)
+
+type (
+	DedupOptions struct {
+		window  time.Duration
+		burst   int
+		fields  []string
+		maxKeys int
+	}
+
+	DedupOpt func(opts *DedupOptions)
+
+	// dedupEntry tracks the duplicates of an event within a window
+	dedupEntry struct {
+		out   io.Writer
+		level zerolog.Level
+		event []byte
+		count int
+		timer *time.Timer
+	}
+
+	// dedupWriter suppresses duplicate events before writing them to the wrapped writer
+	dedupWriter struct {
+		dedup *Deduplicator
+		out   io.Writer
+	}
+)
+
+// Deduplicator collapses identical events, having the same level, message and selected fields, within a window.
+// The first events of a window are written and the following ones are suppressed. When the window closes,
+// a summary is written: the first event with the number of suppressed duplicates in the RepeatedFieldName field
+// and a text such as "repeated 512 times in 10s" in the SummaryFieldName field.
+type Deduplicator struct {
+	opts    *DedupOptions
+	mu      sync.Mutex
+	entries map[string]*dedupEntry
+	dropped uint64
+}
+
+func newDedupOptions(options []DedupOpt) *DedupOptions {
+	opts := &DedupOptions{
+		window:  10 * time.Second,
+		burst:   1,
+		maxKeys: 10000,
+	}
+
+	for _, set := range options {
+		set(opts)
+	}
+
+	return opts
+}
+
+// WithDedupWindow allows to specify the window duplicates are collapsed within. By default, it is set to 10 seconds.
+func WithDedupWindow(window time.Duration) DedupOpt {
+	return func(opts *DedupOptions) {
+		opts.window = window
+	}
+}
+
+// WithDedupBurst allows to specify how many identical events are written per window before suppressing the others.
+// By default, it is set to 1.
+func WithDedupBurst(burst int) DedupOpt {
+	return func(opts *DedupOptions) {
+		opts.burst = burst
+	}
+}
+
+// WithDedupFields allows to specify fields whose values also identify an event, so that events with the same
+// message and different values are not collapsed. By default, only the level and message identify an event.
+func WithDedupFields(fields ...string) DedupOpt {
+	return func(opts *DedupOptions) {
+		opts.fields = append(opts.fields, fields...)
+	}
+}
+
+// WithDedupMaxKeys allows to specify how many distinct events are tracked at once.
+// Events beyond it are written without deduplication. By default, it is set to 10000.
+func WithDedupMaxKeys(maxKeys int) DedupOpt {
+	return func(opts *DedupOptions) {
+		opts.maxKeys = maxKeys
+	}
+}
+
+// WithDeduplication suppresses duplicate events of the logger before they are written to its output,
+// see Deduplicator. Use NewDeduplicator with WithDeduplicator to flush pending summaries on shutdown.
+func WithDeduplication(options ...DedupOpt) Opt {
+	return WithDeduplicator(NewDeduplicator(options...))
+}
+
+// WithDeduplicator suppresses duplicate events of the logger with d before they are written to its output
+func WithDeduplicator(d *Deduplicator) Opt {
+	return func(opts *Options) {
+		opts.stages = append(opts.stages, unnamedStage(d.Writer))
+	}
+}
+
+// NewDeduplicator returns a Deduplicator configured by the options
+func NewDeduplicator(options ...DedupOpt) *Deduplicator {
+	return &Deduplicator{
+		opts:    newDedupOptions(options),
+		entries: map[string]*dedupEntry{},
+	}
+}
+
+// Writer returns a writer that suppresses duplicate events before writing them to out
+func (d *Deduplicator) Writer(out io.Writer) io.Writer {
+	return &dedupWriter{dedup: d, out: out}
+}
+
+// Dropped returns the number of suppressed duplicates, so that the Deduplicator can be tracked by Metrics
+func (d *Deduplicator) Dropped() uint64 {
+	return atomic.LoadUint64(&d.dropped)
+}
+
+// Flush closes every window, writing the summaries of the events with suppressed duplicates
+func (d *Deduplicator) Flush() {
+	d.mu.Lock()
+	entries := d.entries
+	d.entries = map[string]*dedupEntry{}
+	for _, entry := range entries {
+		entry.timer.Stop()
+	}
+	d.mu.Unlock()
+
+	for _, entry := range entries {
+		d.summarize(entry)
+	}
+}
+
+// suppress reports whether the event is a duplicate to suppress, tracking it otherwise
+func (d *Deduplicator) suppress(out io.Writer, level zerolog.Level, p []byte) bool {
+	key, ok := d.key(level, p)
+	if !ok {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	entry, ok := d.entries[key]
+	if !ok {
+		if len(d.entries) >= d.opts.maxKeys {
+			return false
+		}
+
+		entry = &dedupEntry{out: out, level: level, event: append([]byte(nil), p...)}
+		entry.timer = time.AfterFunc(d.opts.window, func() { d.expire(key, entry) })
+		d.entries[key] = entry
+	}
+
+	entry.count++
+	if entry.count <= d.opts.burst {
+		return false
+	}
+
+	atomic.AddUint64(&d.dropped, 1)
+	return true
+}
+
+// expire closes the window of the entry, writing its summary
+func (d *Deduplicator) expire(key string, entry *dedupEntry) {
+	d.mu.Lock()
+	if d.entries[key] != entry {
+		d.mu.Unlock()
+		return
+	}
+	delete(d.entries, key)
+	d.mu.Unlock()
+
+	d.summarize(entry)
+}
+
+// summarize writes the summary of the entry if duplicates were suppressed
+func (d *Deduplicator) summarize(entry *dedupEntry) {
+	repeated := entry.count - d.opts.burst
+	if repeated <= 0 {
+		return
+	}
+
+	event := bytes.TrimRight(entry.event, "\n")
+	if len(event) < 2 || event[len(event)-1] != '}' {
+		return
+	}
+
+	summary := make([]byte, 0, len(event)+64)
+	summary = append(summary, event[:len(event)-1]...)
+	if len(event) > 2 {
+		summary = append(summary, ',')
+	}
+	summary = append(summary, `"`+RepeatedFieldName+`":`...)
+	summary = strconv.AppendInt(summary, int64(repeated), 10)
+	summary = append(summary, `,"`+SummaryFieldName+`":"repeated `...)
+	summary = strconv.AppendInt(summary, int64(repeated), 10)
+	summary = append(summary, " times in "+d.opts.window.String()+"\"}\n"...)
+
+	if lw, ok := entry.out.(zerolog.LevelWriter); ok {
+		_, _ = lw.WriteLevel(entry.level, summary)
+		return
+	}
+	_, _ = entry.out.Write(summary)
+}
+
+// key returns the identity of an encoded event: its level, message and selected fields
+func (d *Deduplicator) key(level zerolog.Level, p []byte) (string, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(p, &fields); err != nil {
+		return "", false
+	}
+
+	key := make([]byte, 0, 64)
+	key = append(key, level.String()...)
+	key = append(key, 0)
+	key = append(key, fields[zerolog.MessageFieldName]...)
+	for _, field := range d.opts.fields {
+		key = append(key, 0)
+		key = append(key, fields[field]...)
+	}
+
+	return string(key), true
+}
+
+func (w *dedupWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *dedupWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if w.dedup.suppress(w.out, level, p) {
+		return len(p), nil
+	}
+
+	if lw, ok := w.out.(zerolog.LevelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+
+	return w.out.Write(p)
+}
@@ -0,0 +1,122 @@
+package zerolog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDeduplicator(t *testing.T) {
+	b := &bytes.Buffer{}
+	d := NewDeduplicator(WithDedupWindow(time.Hour))
+	l := New(WithOutput(b), WithTimestamp(), WithDeduplicator(d))
+
+	for i := 0; i < 513; i++ {
+		l.Errorf("downstream %s unavailable", "billing")
+	}
+	l.Warnf("downstream %s unavailable", "billing")
+	l.Errorf("downstream %s unavailable", "stripe")
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Len(t, lines, 3)
+	assert.Contains(t, lines[0], `"level":"error"`)
+	assert.Contains(t, lines[1], `"level":"warn"`)
+	assert.Contains(t, lines[2], `"message":"downstream stripe unavailable"`)
+	assert.Equal(t, uint64(512), d.Dropped())
+
+	b.Reset()
+	d.Flush()
+	assert.Equal(t, strings.TrimSuffix(lines[0], "}")+`,"repeated":512,"summary":"repeated 512 times in 1h0m0s"}`+"\n", b.String())
+
+	b.Reset()
+	d.Flush()
+	l.Errorf("downstream %s unavailable", "billing")
+	assert.Contains(t, b.String(), `"message":"downstream billing unavailable"`)
+}
+
+func TestDeduplicationWindow(t *testing.T) {
+	w := newGatedWriter()
+	close(w.gate)
+	l := New(WithOutput(w), WithDeduplication(WithDedupWindow(20*time.Millisecond)))
+
+	l.Error("failed")
+	l.Error("failed")
+	l.Error("failed")
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(w.String(), "summary")
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, `{"level":"error","message":"failed"}
+{"level":"error","message":"failed","repeated":2,"summary":"repeated 2 times in 20ms"}
+`, w.String())
+
+	l.Error("failed")
+	assert.True(t, strings.HasSuffix(w.String(), `"summary":"repeated 2 times in 20ms"}
+{"level":"error","message":"failed"}
+`))
+}
+
+func TestDeduplicationOptions(t *testing.T) {
+	b := &bytes.Buffer{}
+	d := NewDeduplicator(WithDedupWindow(time.Hour), WithDedupBurst(2), WithDedupFields("user"), WithDedupMaxKeys(2))
+	l := New(WithOutput(b), WithDeduplicator(d))
+
+	for i := 0; i < 3; i++ {
+		l.Warnw("denied", "user", "alice", "attempt", i)
+		l.Warnw("denied", "user", "bob", "attempt", i)
+		l.Warnw("other", "user", "bob")
+	}
+	d.Flush()
+
+	assert.Equal(t, `{"level":"warn","user":"alice","attempt":0,"message":"denied"}
+{"level":"warn","user":"bob","attempt":0,"message":"denied"}
+{"level":"warn","user":"bob","message":"other"}
+{"level":"warn","user":"alice","attempt":1,"message":"denied"}
+{"level":"warn","user":"bob","attempt":1,"message":"denied"}
+{"level":"warn","user":"bob","message":"other"}
+{"level":"warn","user":"bob","message":"other"}
+`, strings.Join(withoutSummaries(b.String()), ""))
+	assert.Equal(t, 2, strings.Count(b.String(), `"repeated":1,"summary":"repeated 1 times in 1h0m0s"`))
+	assert.Equal(t, uint64(2), d.Dropped())
+}
+
+func TestDeduplicationMetrics(t *testing.T) {
+	m := NewMetrics()
+	d := NewDeduplicator(WithDedupWindow(time.Hour))
+	m.TrackDropped("dedup", d)
+	l := New(WithOutput(&bytes.Buffer{}), WithDeduplicator(d), WithMetrics(m))
+
+	l.Error("failed")
+	l.Error("failed")
+
+	assert.Equal(t, uint64(1), m.Events("", hlog.LevelError))
+	assert.Equal(t, uint64(1), d.Dropped())
+}
+
+func TestDeduplicationPassThrough(t *testing.T) {
+	b := &bytes.Buffer{}
+	w := NewDeduplicator().Writer(b)
+
+	for i := 0; i < 2; i++ {
+		n, err := w.Write([]byte("not json\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, 9, n)
+	}
+
+	assert.Equal(t, "not json\nnot json\n", b.String())
+}
+
+// withoutSummaries returns the lines of s other than summaries, which flushing writes in no particular order
+func withoutSummaries(s string) []string {
+	var lines []string
+	for _, line := range strings.SplitAfter(s, "\n") {
+		if line != "" && !strings.Contains(line, SummaryFieldName) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,43 @@
+package zerolog
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog"
+)
+
+// BadKey is the key used for a trailing value that has no key
+const BadKey = "!BADKEY"
+
+// keyvals normalizes alternating keys and values so they can be passed to zerolog's Fields.
+// Non-string keys are converted with fmt.Sprint. A trailing value without a key is stored under
+// zerolog.ErrorFieldName when it is an error and under BadKey otherwise.
+func keyvals(kvs []interface{}) []interface{} {
+	if len(kvs) == 0 {
+		return nil
+	}
+
+	fields := make([]interface{}, 0, len(kvs)+1)
+	for i := 0; i < len(kvs); i += 2 {
+		if i+1 == len(kvs) {
+			if err, ok := kvs[i].(error); ok {
+				fields = append(fields, zerolog.ErrorFieldName, err)
+			} else {
+				fields = append(fields, BadKey, kvs[i])
+			}
+			break
+		}
+
+		fields = append(fields, keyString(kvs[i]), kvs[i+1])
+	}
+
+	return fields
+}
+
+func keyString(key interface{}) string {
+	if k, ok := key.(string); ok {
+		return k
+	}
+
+	return fmt.Sprint(key)
+}
@@ -0,0 +1,19 @@
+package zerolog
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyvals(t *testing.T) {
+	err := errors.New("failed")
+
+	assert.Nil(t, keyvals(nil))
+	assert.Equal(t, []interface{}{"a", 1, "b", "c"}, keyvals([]interface{}{"a", 1, "b", "c"}))
+	assert.Equal(t, []interface{}{"1", 2, "true", false}, keyvals([]interface{}{1, 2, true, false}))
+	assert.Equal(t, []interface{}{"a", 1, zerolog.ErrorFieldName, err}, keyvals([]interface{}{"a", 1, err}))
+	assert.Equal(t, []interface{}{"a", 1, BadKey, "b"}, keyvals([]interface{}{"a", 1, "b"}))
+}
@@ -0,0 +1,345 @@
+package zerolog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultRotationTimeFormat is the default format of the timestamp in the name of rotated files
+const DefaultRotationTimeFormat = "2006-01-02T15-04-05.000"
+
+var _ io.WriteCloser = (*FileWriter)(nil)
+
+type (
+	FileWriterOptions struct {
+		maxSize      int64
+		interval     time.Duration
+		timeFormat   string
+		mode         os.FileMode
+		reopenSignal bool
+		compress     bool
+		maxAge       time.Duration
+		maxBackups   int
+		maxTotalSize int64
+		reporter     *Logger
+	}
+
+	FileWriterOpt func(opts *FileWriterOptions)
+)
+
+// FileWriter writes to a file at a stable path and rotates it by size and/or wall-clock interval.
+// Rotated files are renamed to the path with a timestamp inserted before the extension,
+// e.g. app.log is rotated to app-2022-11-11T10-00-00.000.log.
+type FileWriter struct {
+	path string
+	opts *FileWriterOptions
+	now  func() time.Time
+
+	mu           sync.Mutex
+	file         *os.File
+	closed       bool
+	size         int64
+	nextRotation time.Time
+
+	signals chan os.Signal
+	done    chan struct{}
+
+	cleanupMu   sync.Mutex
+	cleanupCh   chan struct{}
+	cleanupDone chan struct{}
+}
+
+func newFileWriterOptions(options []FileWriterOpt) *FileWriterOptions {
+	opts := &FileWriterOptions{
+		timeFormat: DefaultRotationTimeFormat,
+		mode:       0o644,
+	}
+
+	for _, set := range options {
+		set(opts)
+	}
+
+	return opts
+}
+
+// WithMaxFileSize rotates the file before a write would make it larger than size bytes. By default, files are not rotated by size.
+func WithMaxFileSize(size int64) FileWriterOpt {
+	return func(opts *FileWriterOptions) {
+		opts.maxSize = size
+	}
+}
+
+// WithRotationInterval rotates the file at every multiple of interval on the wall clock, e.g. every hour on the hour.
+// By default, files are not rotated by time.
+func WithRotationInterval(interval time.Duration) FileWriterOpt {
+	return func(opts *FileWriterOptions) {
+		opts.interval = interval
+	}
+}
+
+// WithRotationTimeFormat allows to specify the format of the timestamp in the name of rotated files.
+// By default, it is set to DefaultRotationTimeFormat.
+func WithRotationTimeFormat(format string) FileWriterOpt {
+	return func(opts *FileWriterOptions) {
+		opts.timeFormat = format
+	}
+}
+
+// WithFileMode allows to specify the permissions of created log files. By default, it is set to 0644.
+func WithFileMode(mode os.FileMode) FileWriterOpt {
+	return func(opts *FileWriterOptions) {
+		opts.mode = mode
+	}
+}
+
+// WithReopenOnSIGHUP reopens the file when the process receives SIGHUP, for compatibility with logrotate
+func WithReopenOnSIGHUP() FileWriterOpt {
+	return func(opts *FileWriterOptions) {
+		opts.reopenSignal = true
+	}
+}
+
+// WithFileOutput allows to specify a file the logger writes to, rotated according to the file writer options.
+// The file is opened once, when WithFileOutput is called, and shared by every logger built with the option.
+// If the file cannot be opened, the error is reported on os.Stderr and the output is left unchanged.
+// Use NewFileWriter with WithOutput to handle the error or to close the file on shutdown.
+func WithFileOutput(path string, options ...FileWriterOpt) Opt {
+	w, err := NewFileWriter(path, options...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "zerolog: could not open log file: %v\n", err)
+	}
+
+	return func(opts *Options) {
+		if err != nil {
+			return
+		}
+		opts.context = opts.context.Logger().Output(w).With()
+		opts.out = w
+	}
+}
+
+// NewFileWriter opens, creating it and its directory if needed, the file at path for appending
+func NewFileWriter(path string, options ...FileWriterOpt) (*FileWriter, error) {
+	w := &FileWriter{
+		path: path,
+		opts: newFileWriterOptions(options),
+		now:  time.Now,
+	}
+
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	if w.opts.reopenSignal {
+		w.signals = make(chan os.Signal, 1)
+		w.done = make(chan struct{})
+		signal.Notify(w.signals, syscall.SIGHUP)
+		go w.handleSignals()
+	}
+
+	if w.opts.retention() {
+		w.cleanupCh = make(chan struct{}, 1)
+		w.cleanupDone = make(chan struct{})
+		w.cleanupCh <- struct{}{}
+		go w.runCleanup()
+	}
+
+	return w, nil
+}
+
+// Write writes p to the file, rotating it first if needed
+func (w *FileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if w.shouldRotate(len(p)) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+
+	return n, err
+}
+
+// Rotate renames the current file to a timestamped name and opens a new file at the path
+func (w *FileWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return os.ErrClosed
+	}
+
+	return w.rotate()
+}
+
+// Reopen closes and reopens the file at the path, e.g. after it has been moved by an external tool.
+// It also opens the file again after a failed rotation left the writer without file.
+func (w *FileWriter) Reopen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return os.ErrClosed
+	}
+
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			return w.reopenAfter(err)
+		}
+	}
+
+	return w.reopen()
+}
+
+// Close closes the file, stops listening for SIGHUP and waits for a running cleanup to finish
+func (w *FileWriter) Close() error {
+	w.mu.Lock()
+
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+
+	if w.signals != nil {
+		signal.Stop(w.signals)
+		close(w.done)
+	}
+
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+		w.file = nil
+	}
+	w.mu.Unlock()
+
+	if w.cleanupCh != nil {
+		close(w.cleanupCh)
+		<-w.cleanupDone
+	}
+
+	return err
+}
+
+// Path returns the path of the current file
+func (w *FileWriter) Path() string {
+	return w.path
+}
+
+func (w *FileWriter) shouldRotate(n int) bool {
+	if w.opts.maxSize > 0 && w.size > 0 && w.size+int64(n) > w.opts.maxSize {
+		return true
+	}
+
+	return w.opts.interval > 0 && !w.now().Before(w.nextRotation)
+}
+
+func (w *FileWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return w.reopenAfter(err)
+	}
+
+	if w.size > 0 {
+		if err := os.Rename(w.path, w.rotatedName(w.now())); err != nil {
+			return w.reopenAfter(err)
+		}
+
+		if w.cleanupCh != nil {
+			select {
+			case w.cleanupCh <- struct{}{}:
+			default:
+			}
+		}
+	}
+
+	return w.reopen()
+}
+
+// reopen opens the file at the path again after the current file was closed.
+// If it fails, the writer is left without file, so that writes fail with os.ErrClosed until it is reopened.
+func (w *FileWriter) reopen() error {
+	if err := w.open(); err != nil {
+		w.file = nil
+		return err
+	}
+
+	return nil
+}
+
+// reopenAfter reopens the file after err interrupted a rotation that had closed it, returning err together with
+// the error of reopening, if any
+func (w *FileWriter) reopenAfter(err error) error {
+	if openErr := w.reopen(); openErr != nil {
+		return fmt.Errorf("%w, and reopening the file failed: %v", err, openErr)
+	}
+
+	return err
+}
+
+func (w *FileWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, w.opts.mode)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	w.file = file
+	w.size = info.Size()
+	if w.opts.interval > 0 {
+		w.nextRotation = w.now().Truncate(w.opts.interval).Add(w.opts.interval)
+	}
+
+	return nil
+}
+
+// rotatedName returns an unused name for the file rotated at t, or the first name that cannot be checked,
+// leaving the error to renaming
+func (w *FileWriter) rotatedName(t time.Time) string {
+	ext := filepath.Ext(w.path)
+	base := strings.TrimSuffix(w.path, ext)
+	name := base + "-" + t.Format(w.opts.timeFormat)
+
+	rotated := name + ext
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(rotated); err != nil {
+			return rotated
+		}
+		rotated = name + "." + strconv.Itoa(i) + ext
+	}
+}
+
+func (w *FileWriter) handleSignals() {
+	for {
+		select {
+		case <-w.signals:
+			_ = w.Reopen()
+		case <-w.done:
+			return
+		}
+	}
+}
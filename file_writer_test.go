@@ -0,0 +1,198 @@
+package zerolog
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func readDir(t *testing.T, dir string) []string {
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func readFile(t *testing.T, path string) string {
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func TestFileWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+
+	w, err := NewFileWriter(path)
+	assert.NoError(t, err)
+
+	l := New(WithOutput(w))
+	l.Info("foo")
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, `{"level":"info","message":"foo"}
+`, readFile(t, path))
+	assert.Equal(t, path, w.Path())
+
+	_, err = w.Write([]byte("bar"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestFileWriterMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	now := time.Date(2022, 11, 11, 10, 0, 0, 0, time.UTC)
+
+	w, err := NewFileWriter(path, WithMaxFileSize(10))
+	assert.NoError(t, err)
+	w.now = func() time.Time { return now }
+
+	_, _ = w.Write([]byte("12345678\n"))
+	_, _ = w.Write([]byte("abcdefgh\n"))
+	_, _ = w.Write([]byte("ABCDEFGH\n"))
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{
+		"app-2022-11-11T10-00-00.000.1.log",
+		"app-2022-11-11T10-00-00.000.log",
+		"app.log",
+	}, readDir(t, dir))
+	assert.Equal(t, "12345678\n", readFile(t, filepath.Join(dir, "app-2022-11-11T10-00-00.000.log")))
+	assert.Equal(t, "abcdefgh\n", readFile(t, filepath.Join(dir, "app-2022-11-11T10-00-00.000.1.log")))
+	assert.Equal(t, "ABCDEFGH\n", readFile(t, path))
+}
+
+func TestFileWriterInterval(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	now := time.Date(2022, 11, 11, 10, 30, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	w, err := NewFileWriter(path, WithRotationInterval(time.Hour), WithRotationTimeFormat("2006010215"))
+	assert.NoError(t, err)
+	w.now = clock
+	w.nextRotation = now.Truncate(time.Hour).Add(time.Hour)
+
+	_, _ = w.Write([]byte("first\n"))
+	now = now.Add(20 * time.Minute)
+	_, _ = w.Write([]byte("second\n"))
+	now = now.Add(20 * time.Minute)
+	_, _ = w.Write([]byte("third\n"))
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"app-2022111111.log", "app.log"}, readDir(t, dir))
+	assert.Equal(t, "first\nsecond\n", readFile(t, filepath.Join(dir, "app-2022111111.log")))
+	assert.Equal(t, "third\n", readFile(t, path))
+}
+
+func TestFileWriterRotateAndReopen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	w, err := NewFileWriter(path, WithReopenOnSIGHUP())
+	assert.NoError(t, err)
+
+	_, _ = w.Write([]byte("first\n"))
+	assert.NoError(t, os.Rename(path, filepath.Join(dir, "app.log.1")))
+	process, err := os.FindProcess(os.Getpid())
+	assert.NoError(t, err)
+	assert.NoError(t, process.Signal(syscall.SIGHUP))
+
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+
+	_, _ = w.Write([]byte("second\n"))
+	assert.NoError(t, w.Rotate())
+	_, _ = w.Write([]byte("third\n"))
+	assert.NoError(t, w.Close())
+
+	names := readDir(t, dir)
+	assert.Len(t, names, 3)
+	assert.Equal(t, "first\n", readFile(t, filepath.Join(dir, "app.log.1")))
+	assert.Equal(t, "second\n", readFile(t, filepath.Join(dir, names[0])))
+	assert.Equal(t, "third\n", readFile(t, path))
+}
+
+func TestFileWriterRotateFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	w, err := NewFileWriter(path, WithMaxFileSize(10))
+	assert.NoError(t, err)
+
+	_, _ = w.Write([]byte("12345678\n"))
+	assert.NoError(t, os.Remove(path))
+
+	_, err = w.Write([]byte("abcdefgh\n"))
+	assert.Error(t, err)
+
+	_, err = w.Write([]byte("ABCDEFGH\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"app.log"}, readDir(t, dir))
+	assert.Equal(t, "ABCDEFGH\n", readFile(t, path))
+}
+
+func TestFileWriterReopenFailure(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	path := filepath.Join(dir, "app.log")
+
+	w, err := NewFileWriter(path, WithMaxFileSize(10))
+	assert.NoError(t, err)
+
+	_, _ = w.Write([]byte("12345678\n"))
+	assert.NoError(t, os.RemoveAll(dir))
+	assert.NoError(t, os.WriteFile(dir, nil, 0o644))
+
+	_, err = w.Write([]byte("abcdefgh\n"))
+	assert.ErrorIs(t, err, syscall.ENOTDIR)
+	assert.Contains(t, err.Error(), "reopening the file failed")
+
+	_, err = w.Write([]byte("abcdefgh\n"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+
+	assert.NoError(t, os.Remove(dir))
+	assert.NoError(t, w.Reopen())
+	_, err = w.Write([]byte("ABCDEFGH\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, "ABCDEFGH\n", readFile(t, path))
+	assert.ErrorIs(t, w.Reopen(), os.ErrClosed)
+}
+
+func TestWithFileOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	l := New(WithFileOutput(path, WithMaxFileSize(1024)))
+	l.Info("foo")
+
+	assert.Equal(t, `{"level":"info","message":"foo"}
+`, readFile(t, path))
+}
+
+func TestWithFileOutputOpensOnce(t *testing.T) {
+	opt := WithFileOutput(filepath.Join(t.TempDir(), "app.log"))
+
+	first := newOptions(zerolog.New(nil), nil, []Opt{opt})
+	second := newOptions(zerolog.New(nil), nil, []Opt{opt})
+
+	assert.IsType(t, &FileWriter{}, first.out)
+	assert.Same(t, first.out, second.out)
+	assert.NoError(t, first.out.(*FileWriter).Close())
+}
@@ -0,0 +1,271 @@
+package zerolog
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/cloudwego/hertz/pkg/common/json"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// RootLoggerName is the name under which the level handler reports the default logger
+const RootLoggerName = "root"
+
+type (
+	LevelHandlerOptions struct {
+		logger   *Logger
+		loggers  map[string]*Logger
+		registry *Registry
+	}
+
+	LevelHandlerOpt func(opts *LevelHandlerOptions)
+
+	levelRequest struct {
+		Logger string `json:"logger"`
+		Level  string `json:"level"`
+		TTL    string `json:"ttl"`
+	}
+
+	// levelOverride is a temporary level change that reverts to previous when its timer fires,
+	// or lets a named logger follow its root again if it had no level of its own
+	levelOverride struct {
+		previous  hlog.Level
+		inherited bool
+		expiresAt time.Time
+		timer     *time.Timer
+	}
+
+	levelHandler struct {
+		opts      *LevelHandlerOptions
+		mu        sync.Mutex
+		overrides map[*Logger]*levelOverride
+	}
+)
+
+func newLevelHandlerOptions(options []LevelHandlerOpt) *LevelHandlerOptions {
+	opts := &LevelHandlerOptions{
+		loggers:  map[string]*Logger{},
+		registry: defaultRegistry,
+	}
+
+	for _, set := range options {
+		set(opts)
+	}
+
+	return opts
+}
+
+// WithLevelHandlerLogger allows to specify the default logger managed by the level handler.
+// By default, the logger returned by GetLogger at request time is used.
+func WithLevelHandlerLogger(logger *Logger) LevelHandlerOpt {
+	return func(opts *LevelHandlerOptions) {
+		opts.logger = logger
+	}
+}
+
+// WithNamedLevelLogger adds a named logger whose level can be viewed and changed by the level handler.
+// It takes precedence over a logger with the same name in the registry.
+func WithNamedLevelLogger(name string, logger *Logger) LevelHandlerOpt {
+	return func(opts *LevelHandlerOptions) {
+		opts.loggers[name] = logger
+	}
+}
+
+// WithLevelHandlerRegistry allows to specify the registry whose named loggers are managed by the level handler.
+// By default, the registry used by Named is managed.
+func WithLevelHandlerRegistry(registry *Registry) LevelHandlerOpt {
+	return func(opts *LevelHandlerOptions) {
+		opts.registry = registry
+	}
+}
+
+// LevelHandler returns a handler to view and change logger levels at runtime. Mount it for GET and PUT on any route.
+//
+// GET returns the levels of all loggers, or of a single logger when the logger query parameter is set.
+// PUT changes the level of the logger named by logger (the default logger when empty), read from a JSON body
+// such as {"logger":"billing","level":"debug","ttl":"5m"} or from query parameters with the same names.
+// When ttl is set, the level reverts to its previous value once it has elapsed, and a named logger without
+// level of its own follows the level of its root again.
+func LevelHandler(options ...LevelHandlerOpt) app.HandlerFunc {
+	h := &levelHandler{
+		opts:      newLevelHandlerOptions(options),
+		overrides: map[*Logger]*levelOverride{},
+	}
+
+	return func(ctx context.Context, c *app.RequestContext) {
+		switch string(c.Method()) {
+		case consts.MethodGet:
+			h.get(c)
+		case consts.MethodPut:
+			h.put(c)
+		default:
+			c.Response.Header.Set("Allow", "GET, PUT")
+			c.JSON(consts.StatusMethodNotAllowed, utils.H{"error": "method not allowed"})
+		}
+	}
+}
+
+func (h *levelHandler) get(c *app.RequestContext) {
+	if name := c.Query("logger"); name != "" {
+		logger, ok := h.logger(name)
+		if !ok {
+			c.JSON(consts.StatusNotFound, utils.H{"error": fmt.Sprintf("unknown logger %q", name)})
+			return
+		}
+
+		c.JSON(consts.StatusOK, h.describe(name, logger))
+		return
+	}
+
+	names := h.names()
+	loggers := make([]utils.H, 0, len(names))
+	for _, name := range names {
+		if logger, ok := h.logger(name); ok {
+			loggers = append(loggers, h.describe(name, logger))
+		}
+	}
+
+	c.JSON(consts.StatusOK, utils.H{"loggers": loggers})
+}
+
+func (h *levelHandler) put(c *app.RequestContext) {
+	req := levelRequest{
+		Logger: c.Query("logger"),
+		Level:  c.Query("level"),
+		TTL:    c.Query("ttl"),
+	}
+	if body := c.Request.Body(); len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			c.JSON(consts.StatusBadRequest, utils.H{"error": fmt.Sprintf("invalid request body: %v", err)})
+			return
+		}
+	}
+
+	name := req.Logger
+	if name == "" {
+		name = RootLoggerName
+	}
+
+	logger, ok := h.logger(name)
+	if !ok {
+		c.JSON(consts.StatusNotFound, utils.H{"error": fmt.Sprintf("unknown logger %q", name)})
+		return
+	}
+
+	level, err := ParseLevel(req.Level)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return
+	}
+
+	var ttl time.Duration
+	if req.TTL != "" {
+		ttl, err = time.ParseDuration(req.TTL)
+		if err != nil || ttl < 0 {
+			c.JSON(consts.StatusBadRequest, utils.H{"error": fmt.Sprintf("invalid ttl %q", req.TTL)})
+			return
+		}
+	}
+
+	h.setLevel(logger, level, ttl)
+	c.JSON(consts.StatusOK, h.describe(name, logger))
+}
+
+// setLevel changes the level of logger, scheduling a revert to the level it had before the first
+// pending override when ttl is positive
+func (h *levelHandler) setLevel(logger *Logger, level hlog.Level, ttl time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	previous, inherited := logger.GetLevel(), logger.named != nil && !logger.named.hasLevel()
+	if override, ok := h.overrides[logger]; ok {
+		override.timer.Stop()
+		previous, inherited = override.previous, override.inherited
+		delete(h.overrides, logger)
+	}
+
+	logger.SetLevel(level)
+
+	if ttl <= 0 {
+		return
+	}
+
+	override := &levelOverride{previous: previous, inherited: inherited, expiresAt: time.Now().Add(ttl)}
+	override.timer = time.AfterFunc(ttl, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		if h.overrides[logger] != override {
+			return
+		}
+
+		if override.inherited {
+			logger.named.clearLevel()
+		} else {
+			logger.SetLevel(override.previous)
+		}
+		delete(h.overrides, logger)
+	})
+	h.overrides[logger] = override
+}
+
+func (h *levelHandler) describe(name string, logger *Logger) utils.H {
+	desc := utils.H{"logger": name, "level": levelName(logger.GetLevel())}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if override, ok := h.overrides[logger]; ok {
+		desc["expires_at"] = override.expiresAt.Format(time.RFC3339)
+		revertTo := override.previous
+		if override.inherited {
+			revertTo = logger.named.registry.rootLogger().GetLevel()
+		}
+		desc["revert_to"] = levelName(revertTo)
+	}
+
+	return desc
+}
+
+func (h *levelHandler) logger(name string) (*Logger, bool) {
+	if name == RootLoggerName {
+		logger := h.opts.logger
+		if logger == nil {
+			logger = GetLogger()
+		}
+		return logger, logger != nil
+	}
+
+	if logger, ok := h.opts.loggers[name]; ok {
+		return logger, true
+	}
+
+	if h.opts.registry != nil {
+		return h.opts.registry.Lookup(name)
+	}
+
+	return nil, false
+}
+
+func (h *levelHandler) names() []string {
+	names := make([]string, 0, len(h.opts.loggers)+1)
+	for name := range h.opts.loggers {
+		names = append(names, name)
+	}
+	if h.opts.registry != nil {
+		for _, name := range h.opts.registry.Names() {
+			if _, ok := h.opts.loggers[name]; !ok {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	return append([]string{RootLoggerName}, names...)
+}
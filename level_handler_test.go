@@ -0,0 +1,181 @@
+package zerolog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/common/config"
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/cloudwego/hertz/pkg/common/json"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/cloudwego/hertz/pkg/route"
+	"github.com/stretchr/testify/assert"
+)
+
+type LevelResponse struct {
+	Logger    string `json:"logger"`
+	Level     string `json:"level"`
+	ExpiresAt string `json:"expires_at"`
+	RevertTo  string `json:"revert_to"`
+	Error     string `json:"error"`
+}
+
+func newLevelEngine(options ...LevelHandlerOpt) *route.Engine {
+	engine := route.NewEngine(config.NewOptions([]config.Option{}))
+	handler := LevelHandler(options...)
+	engine.GET("/admin/log/level", handler)
+	engine.PUT("/admin/log/level", handler)
+	engine.POST("/admin/log/level", handler)
+	return engine
+}
+
+func TestLevelHandlerGet(t *testing.T) {
+	root := New(WithLevel(hlog.LevelInfo))
+	billing := New(WithLevel(hlog.LevelDebug))
+	engine := newLevelEngine(WithLevelHandlerLogger(root), WithNamedLevelLogger("billing", billing))
+
+	w := ut.PerformRequest(engine, "GET", "/admin/log/level", nil)
+
+	resp := &struct {
+		Loggers []LevelResponse `json:"loggers"`
+	}{}
+	err := json.Unmarshal(w.Body.Bytes(), resp)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, []LevelResponse{
+		{Logger: "root", Level: "info"},
+		{Logger: "billing", Level: "debug"},
+	}, resp.Loggers)
+
+	w = ut.PerformRequest(engine, "GET", "/admin/log/level?logger=billing", nil)
+
+	level := &LevelResponse{}
+	err = json.Unmarshal(w.Body.Bytes(), level)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "debug", level.Level)
+
+	w = ut.PerformRequest(engine, "GET", "/admin/log/level?logger=unknown", nil)
+	assert.Equal(t, 404, w.Code)
+}
+
+func TestLevelHandlerGetDefaultLogger(t *testing.T) {
+	hlog.SetLogger(New(WithLevel(hlog.LevelError)))
+	engine := newLevelEngine()
+
+	w := ut.PerformRequest(engine, "GET", "/admin/log/level?logger=root", nil)
+
+	level := &LevelResponse{}
+	err := json.Unmarshal(w.Body.Bytes(), level)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "error", level.Level)
+}
+
+func TestLevelHandlerPut(t *testing.T) {
+	root := New(WithLevel(hlog.LevelInfo))
+	billing := New(WithLevel(hlog.LevelWarn))
+	engine := newLevelEngine(WithLevelHandlerLogger(root), WithNamedLevelLogger("billing", billing))
+
+	w := ut.PerformRequest(engine, "PUT", "/admin/log/level",
+		&ut.Body{Body: bytes.NewBufferString(`{"level":"debug"}`), Len: -1})
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, hlog.LevelDebug, root.GetLevel())
+
+	w = ut.PerformRequest(engine, "PUT", "/admin/log/level?logger=billing&level=ERROR", nil)
+
+	level := &LevelResponse{}
+	err := json.Unmarshal(w.Body.Bytes(), level)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "billing", level.Logger)
+	assert.Equal(t, "error", level.Level)
+	assert.Empty(t, level.ExpiresAt)
+	assert.Equal(t, hlog.LevelError, billing.GetLevel())
+}
+
+func TestLevelHandlerPutErrors(t *testing.T) {
+	root := New(WithLevel(hlog.LevelInfo))
+	engine := newLevelEngine(WithLevelHandlerLogger(root))
+
+	w := ut.PerformRequest(engine, "PUT", "/admin/log/level?level=verbose", nil)
+	assert.Equal(t, 400, w.Code)
+
+	w = ut.PerformRequest(engine, "PUT", "/admin/log/level?level=debug&ttl=soon", nil)
+	assert.Equal(t, 400, w.Code)
+
+	w = ut.PerformRequest(engine, "PUT", "/admin/log/level",
+		&ut.Body{Body: bytes.NewBufferString(`{"level":`), Len: -1})
+	assert.Equal(t, 400, w.Code)
+
+	w = ut.PerformRequest(engine, "PUT", "/admin/log/level?logger=unknown&level=debug", nil)
+	assert.Equal(t, 404, w.Code)
+
+	w = ut.PerformRequest(engine, "POST", "/admin/log/level?level=debug", nil)
+	assert.Equal(t, 405, w.Code)
+
+	assert.Equal(t, hlog.LevelInfo, root.GetLevel())
+}
+
+func TestLevelHandlerTTL(t *testing.T) {
+	root := New(WithLevel(hlog.LevelInfo))
+	engine := newLevelEngine(WithLevelHandlerLogger(root))
+
+	w := ut.PerformRequest(engine, "PUT", "/admin/log/level?level=debug&ttl=1h", nil)
+	ut.PerformRequest(engine, "PUT", "/admin/log/level?level=trace&ttl=50ms", nil)
+
+	level := &LevelResponse{}
+	err := json.Unmarshal(w.Body.Bytes(), level)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, level.ExpiresAt)
+	assert.Equal(t, "info", level.RevertTo)
+	assert.Equal(t, hlog.LevelTrace, root.GetLevel())
+
+	assert.Eventually(t, func() bool {
+		return root.GetLevel() == hlog.LevelInfo
+	}, time.Second, 10*time.Millisecond)
+
+	ut.PerformRequest(engine, "PUT", "/admin/log/level?level=debug&ttl=50ms", nil)
+	ut.PerformRequest(engine, "PUT", "/admin/log/level?level=error", nil)
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Equal(t, hlog.LevelError, root.GetLevel())
+}
+
+func TestLevelHandlerTTLNamed(t *testing.T) {
+	root := New(WithLevel(hlog.LevelInfo))
+	r := NewRegistry(root)
+	billing := r.Named("billing")
+	engine := newLevelEngine(WithLevelHandlerLogger(root), WithLevelHandlerRegistry(r))
+
+	w := ut.PerformRequest(engine, "PUT", "/admin/log/level?logger=billing&level=debug&ttl=20ms", nil)
+
+	level := &LevelResponse{}
+	err := json.Unmarshal(w.Body.Bytes(), level)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "info", level.RevertTo)
+	assert.Equal(t, hlog.LevelDebug, billing.GetLevel())
+
+	assert.Eventually(t, func() bool {
+		return billing.GetLevel() == hlog.LevelInfo
+	}, time.Second, 10*time.Millisecond)
+
+	root.SetLevel(hlog.LevelError)
+	assert.Equal(t, hlog.LevelError, billing.GetLevel())
+
+	r.SetLevel("billing", hlog.LevelWarn)
+	ut.PerformRequest(engine, "PUT", "/admin/log/level?logger=billing&level=debug&ttl=20ms", nil)
+
+	assert.Eventually(t, func() bool {
+		return billing.GetLevel() == hlog.LevelWarn
+	}, time.Second, 10*time.Millisecond)
+
+	root.SetLevel(hlog.LevelInfo)
+	assert.Equal(t, hlog.LevelWarn, billing.GetLevel())
+}
@@ -0,0 +1,84 @@
+package zerolog
+
+import (
+	"io"
+	"sort"
+
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/rs/zerolog"
+)
+
+var _ zerolog.LevelWriter = (*LevelSplitWriter)(nil)
+
+// LevelSplitWriter routes events to writers by level. Each writer receives the events from its minimum level up to
+// the minimum level of the next writer, e.g. errors to os.Stderr and everything else to os.Stdout.
+type LevelSplitWriter struct {
+	levels  []zerolog.Level
+	writers []io.Writer
+}
+
+// NewLevelSplitWriter returns a writer routing events to the writers keyed by their minimum level.
+// Events below the lowest level are dropped and events without level, such as those written with Write,
+// go to the writer of the lowest level. Notice and warn share a zerolog level, so warn takes precedence.
+func NewLevelSplitWriter(writers map[hlog.Level]io.Writer) *LevelSplitWriter {
+	levels := make([]hlog.Level, 0, len(writers))
+	for level := range writers {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+
+	w := &LevelSplitWriter{}
+	for _, level := range levels {
+		lvl := matchHlogLevel(level)
+		if n := len(w.levels); n > 0 && w.levels[n-1] == lvl {
+			w.writers[n-1] = writers[level]
+			continue
+		}
+		w.levels = append(w.levels, lvl)
+		w.writers = append(w.writers, writers[level])
+	}
+
+	return w
+}
+
+// WithLevelWriters allows to route events to the writers keyed by their minimum level instead of a single output,
+// see NewLevelSplitWriter
+func WithLevelWriters(writers map[hlog.Level]io.Writer) Opt {
+	return WithOutput(NewLevelSplitWriter(writers))
+}
+
+// Write writes p to the writer of the lowest level
+func (w *LevelSplitWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel writes p, logged at level, to the writer of the highest minimum level at or below level
+func (w *LevelSplitWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	out := w.writer(level)
+	if out == nil {
+		return len(p), nil
+	}
+
+	if lw, ok := out.(zerolog.LevelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+
+	return out.Write(p)
+}
+
+func (w *LevelSplitWriter) writer(level zerolog.Level) io.Writer {
+	if len(w.writers) == 0 {
+		return nil
+	}
+
+	if level == zerolog.NoLevel {
+		return w.writers[0]
+	}
+
+	i := sort.Search(len(w.levels), func(i int) bool { return w.levels[i] > level })
+	if i == 0 {
+		return nil
+	}
+
+	return w.writers[i-1]
+}
@@ -0,0 +1,90 @@
+package zerolog
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingLevelWriter struct {
+	bytes.Buffer
+	levels []zerolog.Level
+}
+
+func (w *recordingLevelWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.levels = append(w.levels, level)
+	return w.Write(p)
+}
+
+func TestWithLevelWriters(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	l := New(WithLevel(hlog.LevelDebug), WithLevelWriters(map[hlog.Level]io.Writer{
+		hlog.LevelTrace: stdout,
+		hlog.LevelError: stderr,
+	}))
+
+	l.Debug("debug")
+	l.Info("info")
+	l.Warn("warn")
+	l.Error("error")
+
+	assert.Equal(t, `{"level":"debug","message":"debug"}
+{"level":"info","message":"info"}
+{"level":"warn","message":"warn"}
+`, stdout.String())
+	assert.Equal(t, `{"level":"error","message":"error"}
+`, stderr.String())
+}
+
+func TestLevelSplitWriterRanges(t *testing.T) {
+	info, warn, errs := &bytes.Buffer{}, &bytes.Buffer{}, &recordingLevelWriter{}
+	w := NewLevelSplitWriter(map[hlog.Level]io.Writer{
+		hlog.LevelInfo:   info,
+		hlog.LevelNotice: &bytes.Buffer{},
+		hlog.LevelWarn:   warn,
+		hlog.LevelError:  errs,
+	})
+
+	for _, level := range []zerolog.Level{zerolog.DebugLevel, zerolog.InfoLevel, zerolog.WarnLevel, zerolog.ErrorLevel, zerolog.FatalLevel} {
+		n, err := w.WriteLevel(level, []byte(level.String()+"\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, len(level.String())+1, n)
+	}
+
+	n, err := w.Write([]byte("nolevel\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 8, n)
+
+	assert.Equal(t, "info\nnolevel\n", info.String())
+	assert.Equal(t, "warn\n", warn.String())
+	assert.Equal(t, "error\nfatal\n", errs.String())
+	assert.Equal(t, []zerolog.Level{zerolog.ErrorLevel, zerolog.FatalLevel}, errs.levels)
+}
+
+func TestLevelSplitWriterStages(t *testing.T) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	l := New(WithLevelWriters(map[hlog.Level]io.Writer{
+		hlog.LevelTrace: stdout,
+		hlog.LevelError: stderr,
+	}), WithRedaction())
+
+	l.Errorw("failed", "token", "t")
+	l.Warnw("slow", "token", "t")
+
+	assert.Equal(t, `{"level":"warn","token":"***","message":"slow"}
+`, stdout.String())
+	assert.Equal(t, `{"level":"error","token":"***","message":"failed"}
+`, stderr.String())
+}
+
+func TestLevelSplitWriterEmpty(t *testing.T) {
+	w := NewLevelSplitWriter(nil)
+
+	n, err := w.Write([]byte("dropped\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 8, n)
+}
@@ -1,6 +1,9 @@
 package zerolog
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/cloudwego/hertz/pkg/common/hlog"
 	"github.com/rs/zerolog"
 )
@@ -24,8 +27,45 @@ var (
 		zerolog.ErrorLevel: hlog.LevelError,
 		zerolog.FatalLevel: hlog.LevelFatal,
 	}
+
+	hlogLevelNames = map[hlog.Level]string{
+		hlog.LevelTrace:  "trace",
+		hlog.LevelDebug:  "debug",
+		hlog.LevelInfo:   "info",
+		hlog.LevelNotice: "notice",
+		hlog.LevelWarn:   "warn",
+		hlog.LevelError:  "error",
+		hlog.LevelFatal:  "fatal",
+	}
 )
 
+// ParseLevel parses an hlog level name such as debug or notice, ignoring case
+func ParseLevel(name string) (hlog.Level, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "warning" {
+		name = "warn"
+	}
+
+	for level, levelName := range hlogLevelNames {
+		if levelName == name {
+			return level, nil
+		}
+	}
+
+	return hlog.LevelWarn, fmt.Errorf("unknown log level %q", name)
+}
+
+// levelName returns the name of an hlog level
+func levelName(level hlog.Level) string {
+	name, found := hlogLevelNames[level]
+
+	if found {
+		return name
+	}
+
+	return hlogLevelNames[hlog.LevelWarn] // Default level
+}
+
 // matchHlogLevel map hlog.Level to zerolog.Level
 func matchHlogLevel(level hlog.Level) zerolog.Level {
 	zlvl, found := zerologLevels[level]
@@ -25,3 +25,29 @@ func TestMatchZerologLevel(t *testing.T) {
 	assert.Equal(t, hlog.LevelError, matchZerologLevel(zerolog.ErrorLevel))
 	assert.Equal(t, hlog.LevelFatal, matchZerologLevel(zerolog.FatalLevel))
 }
+
+func TestParseLevel(t *testing.T) {
+	for name, expected := range map[string]hlog.Level{
+		"trace":   hlog.LevelTrace,
+		"DEBUG":   hlog.LevelDebug,
+		" info ":  hlog.LevelInfo,
+		"notice":  hlog.LevelNotice,
+		"warn":    hlog.LevelWarn,
+		"warning": hlog.LevelWarn,
+		"error":   hlog.LevelError,
+		"fatal":   hlog.LevelFatal,
+	} {
+		level, err := ParseLevel(name)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, level)
+	}
+
+	_, err := ParseLevel("verbose")
+	assert.Error(t, err)
+}
+
+func TestLevelName(t *testing.T) {
+	assert.Equal(t, "notice", levelName(hlog.LevelNotice))
+	assert.Equal(t, "debug", levelName(hlog.LevelDebug))
+	assert.Equal(t, "warn", levelName(hlog.Level(100)))
+}
@@ -5,6 +5,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
 
 	"github.com/cloudwego/hertz/pkg/common/hlog"
 	"github.com/rs/zerolog"
@@ -12,22 +16,46 @@ import (
 
 var _ hlog.FullLogger = (*Logger)(nil)
 
-// Logger is a wrapper around `zerolog.Logger` that provides an implementation of `hlog.FullLogger` interface
+// disabledLogger is logged through by the zero value of Logger, which writes nothing
+var disabledLogger = zerolog.Nop()
+
+// Logger is a wrapper around `zerolog.Logger` that provides an implementation of `hlog.FullLogger` interface.
+// The wrapped logger is swapped atomically, so SetLevel and SetOutput are safe to call while logging.
 type Logger struct {
-	log     zerolog.Logger
-	out     io.Writer
-	level   zerolog.Level
-	options []Opt
-}
+	mu             sync.Mutex
+	log            atomic.Pointer[zerolog.Logger]
+	fields         []interface{}
+	group          string
+	mergeContext   bool
+	stages         []writerStage
+	traceExtractor TraceExtractor
+	options        []Opt
+	name           string
+	out            io.Writer
+	named          *namedLogger
+}
+
+type (
+	ctxLoggerKey struct{}
+
+	// ctxLogger records the Logger attached to a context together with the zerolog logger
+	// it stored, so that a zerolog logger attached later on can be detected
+	ctxLogger struct {
+		logger *Logger
+		log    *zerolog.Logger
+	}
+)
 
 // New returns a new Logger instance
 func New(options ...Opt) *Logger {
-	return newLogger(zerolog.New(os.Stdout), options)
+	return newLogger(zerolog.New(os.Stdout), os.Stdout, options)
 }
 
 // From returns a new Logger instance using existing zerolog log.
+// The output of log cannot be retrieved, so writer stages such as WithRedaction wrap os.Stdout
+// unless an output is given with WithOutput.
 func From(log zerolog.Logger, options ...Opt) *Logger {
-	return newLogger(log, options)
+	return newLogger(log, nil, options)
 }
 
 // GetLogger returns the default logger instance
@@ -42,75 +70,110 @@ func GetLogger() *Logger {
 	return nil
 }
 
-// SetLevel setting logging level for logger
+// GetLevel returns the current logging level of logger
+func (l *Logger) GetLevel() hlog.Level {
+	return matchZerologLevel(l.current().GetLevel())
+}
+
+// SetLevel setting logging level for logger.
+// It is safe to call concurrently with logging; child loggers created before the call keep their level.
 func (l *Logger) SetLevel(level hlog.Level) {
-	lvl := matchHlogLevel(level)
-	l.level = lvl
-	l.log = l.log.Level(lvl)
+	if l.named != nil {
+		l.named.setLevel(level)
+		return
+	}
+
+	l.update(func(log zerolog.Logger) zerolog.Logger {
+		return log.Level(matchHlogLevel(level))
+	})
 }
 
-// SetOutput setting output for logger
+// SetOutput setting output for logger. Writer stages such as WithRedaction are applied to the new output.
+// It is safe to call concurrently with logging; child loggers created before the call keep their output.
 func (l *Logger) SetOutput(writer io.Writer) {
-	l.out = writer
-	l.log = l.log.Output(writer)
+	if l.named != nil {
+		l.named.setOutput(writer)
+		return
+	}
+
+	out := wrapOutput(writer, l.stages, l.name)
+	l.update(func(log zerolog.Logger) zerolog.Logger {
+		l.out = writer
+		return log.Output(out)
+	})
 }
 
 // WithContext returns context with logger attached
 func (l *Logger) WithContext(ctx context.Context) context.Context {
-	return l.log.WithContext(ctx)
+	ctx = l.current().WithContext(ctx)
+	return context.WithValue(ctx, ctxLoggerKey{}, ctxLogger{logger: l, log: zerolog.Ctx(ctx)})
 }
 
-// WithField appends a field to the logger
+// WithField returns a child logger with the field appended, leaving the logger untouched
 func (l *Logger) WithField(key string, value interface{}) *Logger {
-	l.log = l.log.With().Interface(key, value).Logger()
-	return l
+	return l.withFields([]interface{}{key, value})
 }
 
-// Unwrap returns the underlying zerolog logger
+// With returns a child logger with the key/value pairs appended as fields, leaving the logger untouched
+func (l *Logger) With(kvs ...interface{}) *Logger {
+	return l.withFields(keyvals(kvs))
+}
+
+// WithFields returns a child logger with the fields appended in key order, leaving the logger untouched
+func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	kvs := make([]interface{}, 0, 2*len(keys))
+	for _, key := range keys {
+		kvs = append(kvs, key, fields[key])
+	}
+
+	return l.withFields(kvs)
+}
+
+// WithGroup returns a child logger that qualifies the keys of fields added afterwards with the group name,
+// e.g. a field "id" added to WithGroup("user") is logged as "user.id"
+func (l *Logger) WithGroup(name string) *Logger {
+	l = l.resolve()
+	child := l.derive(*l.current())
+	child.group = l.group + name + "."
+	return child
+}
+
+// Unwrap returns a copy of the underlying zerolog logger.
+// The returned logger is a snapshot that does not follow later calls to SetLevel and SetOutput,
+// and changing it, e.g. with UpdateContext, does not change the logger.
 func (l *Logger) Unwrap() *zerolog.Logger {
-	return &l.log
+	log := *l.current()
+	return &log
 }
 
 // Log log using zerolog logger with specified level
 func (l *Logger) Log(level hlog.Level, kvs ...interface{}) {
+	logger := l.current()
 	switch level {
 	case hlog.LevelTrace, hlog.LevelDebug:
-		l.log.Debug().Msg(fmt.Sprint(kvs...))
+		logger.Debug().Msg(fmt.Sprint(kvs...))
 	case hlog.LevelInfo:
-		l.log.Info().Msg(fmt.Sprint(kvs...))
+		logger.Info().Msg(fmt.Sprint(kvs...))
 	case hlog.LevelNotice, hlog.LevelWarn:
-		l.log.Warn().Msg(fmt.Sprint(kvs...))
+		logger.Warn().Msg(fmt.Sprint(kvs...))
 	case hlog.LevelError:
-		l.log.Error().Msg(fmt.Sprint(kvs...))
+		logger.Error().Msg(fmt.Sprint(kvs...))
 	case hlog.LevelFatal:
-		l.log.Fatal().Msg(fmt.Sprint(kvs...))
+		logger.Fatal().Msg(fmt.Sprint(kvs...))
 	default:
-		l.log.Warn().Msg(fmt.Sprint(kvs...))
+		logger.Warn().Msg(fmt.Sprint(kvs...))
 	}
 }
 
 // Logf log using zerolog logger with specified level and formatting
 func (l *Logger) Logf(level hlog.Level, format string, kvs ...interface{}) {
-	switch level {
-	case hlog.LevelTrace, hlog.LevelDebug:
-		l.log.Debug().Msg(fmt.Sprintf(format, kvs...))
-	case hlog.LevelInfo:
-		l.log.Info().Msg(fmt.Sprintf(format, kvs...))
-	case hlog.LevelNotice, hlog.LevelWarn:
-		l.log.Warn().Msg(fmt.Sprintf(format, kvs...))
-	case hlog.LevelError:
-		l.log.Error().Msg(fmt.Sprintf(format, kvs...))
-	case hlog.LevelFatal:
-		l.log.Fatal().Msg(fmt.Sprintf(format, kvs...))
-	default:
-		l.log.Warn().Msg(fmt.Sprintf(format, kvs...))
-	}
-}
-
-// CtxLogf log with logger associated with context.
-// If no logger is associated, DefaultContextLogger is used, unless DefaultContextLogger is nil, in which case a disabled logger is used.
-func (l *Logger) CtxLogf(level hlog.Level, ctx context.Context, format string, kvs ...interface{}) {
-	logger := zerolog.Ctx(ctx)
+	logger := l.current()
 	switch level {
 	case hlog.LevelTrace, hlog.LevelDebug:
 		logger.Debug().Msg(fmt.Sprintf(format, kvs...))
@@ -127,6 +190,30 @@ func (l *Logger) CtxLogf(level hlog.Level, ctx context.Context, format string, k
 	}
 }
 
+// CtxLogf log with logger associated with context.
+// If no logger is associated, the receiver is used.
+// The trace context carried by the context, if any, is added as fields.
+func (l *Logger) CtxLogf(level hlog.Level, ctx context.Context, format string, kvs ...interface{}) {
+	logger := l.ctxLogger(ctx).current()
+	l.traceFields(newEvent(logger, level), ctx).Msg(fmt.Sprintf(format, kvs...))
+}
+
+// Logw log using zerolog logger with specified level, interpreting kvs as alternating keys and values
+// that are added to the log entry as typed fields.
+func (l *Logger) Logw(level hlog.Level, msg string, kvs ...interface{}) {
+	l = l.resolve()
+	newEvent(l.current(), level).Fields(l.groupKeys(keyvals(kvs))).Msg(msg)
+}
+
+// CtxLogw log with logger associated with context, interpreting kvs as alternating keys and values
+// that are added to the log entry as typed fields.
+// If no logger is associated, the receiver is used.
+// The trace context carried by the context, if any, is added as fields.
+func (l *Logger) CtxLogw(level hlog.Level, ctx context.Context, msg string, kvs ...interface{}) {
+	logger := l.ctxLogger(ctx)
+	l.traceFields(newEvent(logger.current(), level), ctx).Fields(logger.groupKeys(keyvals(kvs))).Msg(msg)
+}
+
 // Trace logs a message at trace level.
 func (l *Logger) Trace(v ...interface{}) {
 	l.Log(hlog.LevelTrace, v...)
@@ -197,55 +284,306 @@ func (l *Logger) Fatalf(format string, v ...interface{}) {
 	l.Logf(hlog.LevelError, format, v...)
 }
 
+// Tracew logs a message with key/value pairs at trace level.
+func (l *Logger) Tracew(msg string, kvs ...interface{}) {
+	l.Logw(hlog.LevelTrace, msg, kvs...)
+}
+
+// Debugw logs a message with key/value pairs at debug level.
+func (l *Logger) Debugw(msg string, kvs ...interface{}) {
+	l.Logw(hlog.LevelDebug, msg, kvs...)
+}
+
+// Infow logs a message with key/value pairs at info level.
+func (l *Logger) Infow(msg string, kvs ...interface{}) {
+	l.Logw(hlog.LevelInfo, msg, kvs...)
+}
+
+// Noticew logs a message with key/value pairs at notice level.
+func (l *Logger) Noticew(msg string, kvs ...interface{}) {
+	l.Logw(hlog.LevelNotice, msg, kvs...)
+}
+
+// Warnw logs a message with key/value pairs at warn level.
+func (l *Logger) Warnw(msg string, kvs ...interface{}) {
+	l.Logw(hlog.LevelWarn, msg, kvs...)
+}
+
+// Errorw logs a message with key/value pairs at error level.
+func (l *Logger) Errorw(msg string, kvs ...interface{}) {
+	l.Logw(hlog.LevelError, msg, kvs...)
+}
+
+// Fatalw logs a message with key/value pairs at fatal level.
+func (l *Logger) Fatalw(msg string, kvs ...interface{}) {
+	l.Logw(hlog.LevelFatal, msg, kvs...)
+}
+
 // CtxTracef logs a message at trace level with logger associated with context.
-// If no logger is associated, DefaultContextLogger is used, unless DefaultContextLogger is nil, in which case a disabled logger is used.
+// If no logger is associated, the receiver is used.
 func (l *Logger) CtxTracef(ctx context.Context, format string, v ...interface{}) {
 	l.CtxLogf(hlog.LevelTrace, ctx, format, v...)
 }
 
 // CtxDebugf logs a message at debug level with logger associated with context.
-// If no logger is associated, DefaultContextLogger is used, unless DefaultContextLogger is nil, in which case a disabled logger is used.
+// If no logger is associated, the receiver is used.
 func (l *Logger) CtxDebugf(ctx context.Context, format string, v ...interface{}) {
 	l.CtxLogf(hlog.LevelDebug, ctx, format, v...)
 }
 
 // CtxInfof logs a message at info level with logger associated with context.
-// If no logger is associated, DefaultContextLogger is used, unless DefaultContextLogger is nil, in which case a disabled logger is used.
+// If no logger is associated, the receiver is used.
 func (l *Logger) CtxInfof(ctx context.Context, format string, v ...interface{}) {
 	l.CtxLogf(hlog.LevelInfo, ctx, format, v...)
 }
 
 // CtxNoticef logs a message at notice level with logger associated with context.
-// If no logger is associated, DefaultContextLogger is used, unless DefaultContextLogger is nil, in which case a disabled logger is used.
+// If no logger is associated, the receiver is used.
 func (l *Logger) CtxNoticef(ctx context.Context, format string, v ...interface{}) {
 	l.CtxLogf(hlog.LevelNotice, ctx, format, v...)
 }
 
 // CtxWarnf logs a message at warn level with logger associated with context.
-// If no logger is associated, DefaultContextLogger is used, unless DefaultContextLogger is nil, in which case a disabled logger is used.
+// If no logger is associated, the receiver is used.
 func (l *Logger) CtxWarnf(ctx context.Context, format string, v ...interface{}) {
 	l.CtxLogf(hlog.LevelWarn, ctx, format, v...)
 }
 
 // CtxErrorf logs a message at error level with logger associated with context.
-// If no logger is associated, DefaultContextLogger is used, unless DefaultContextLogger is nil, in which case a disabled logger is used.
+// If no logger is associated, the receiver is used.
 func (l *Logger) CtxErrorf(ctx context.Context, format string, v ...interface{}) {
 	l.CtxLogf(hlog.LevelError, ctx, format, v...)
 }
 
 // CtxFatalf logs a message at fatal level with logger associated with context.
-// If no logger is associated, DefaultContextLogger is used, unless DefaultContextLogger is nil, in which case a disabled logger is used.
+// If no logger is associated, the receiver is used.
 func (l *Logger) CtxFatalf(ctx context.Context, format string, v ...interface{}) {
 	l.CtxLogf(hlog.LevelFatal, ctx, format, v...)
 }
 
-func newLogger(log zerolog.Logger, options []Opt) *Logger {
-	opts := newOptions(log, options)
+// CtxTracew logs a message with key/value pairs at trace level with logger associated with context.
+// If no logger is associated, the receiver is used.
+func (l *Logger) CtxTracew(ctx context.Context, msg string, kvs ...interface{}) {
+	l.CtxLogw(hlog.LevelTrace, ctx, msg, kvs...)
+}
+
+// CtxDebugw logs a message with key/value pairs at debug level with logger associated with context.
+// If no logger is associated, the receiver is used.
+func (l *Logger) CtxDebugw(ctx context.Context, msg string, kvs ...interface{}) {
+	l.CtxLogw(hlog.LevelDebug, ctx, msg, kvs...)
+}
+
+// CtxInfow logs a message with key/value pairs at info level with logger associated with context.
+// If no logger is associated, the receiver is used.
+func (l *Logger) CtxInfow(ctx context.Context, msg string, kvs ...interface{}) {
+	l.CtxLogw(hlog.LevelInfo, ctx, msg, kvs...)
+}
+
+// CtxNoticew logs a message with key/value pairs at notice level with logger associated with context.
+// If no logger is associated, the receiver is used.
+func (l *Logger) CtxNoticew(ctx context.Context, msg string, kvs ...interface{}) {
+	l.CtxLogw(hlog.LevelNotice, ctx, msg, kvs...)
+}
 
-	return &Logger{
-		log:     opts.context.Logger(),
-		out:     nil,
-		level:   opts.level,
-		options: options,
+// CtxWarnw logs a message with key/value pairs at warn level with logger associated with context.
+// If no logger is associated, the receiver is used.
+func (l *Logger) CtxWarnw(ctx context.Context, msg string, kvs ...interface{}) {
+	l.CtxLogw(hlog.LevelWarn, ctx, msg, kvs...)
+}
+
+// CtxErrorw logs a message with key/value pairs at error level with logger associated with context.
+// If no logger is associated, the receiver is used.
+func (l *Logger) CtxErrorw(ctx context.Context, msg string, kvs ...interface{}) {
+	l.CtxLogw(hlog.LevelError, ctx, msg, kvs...)
+}
+
+// CtxFatalw logs a message with key/value pairs at fatal level with logger associated with context.
+// If no logger is associated, the receiver is used.
+func (l *Logger) CtxFatalw(ctx context.Context, msg string, kvs ...interface{}) {
+	l.CtxLogw(hlog.LevelFatal, ctx, msg, kvs...)
+}
+
+// ctxLogger returns the logger associated with context, falling back to the receiver.
+// When merging is enabled, the fields of a Logger associated with context are layered on top of the receiver.
+func (l *Logger) ctxLogger(ctx context.Context) *Logger {
+	zl := zerolog.Ctx(ctx)
+
+	if attached, ok := ctx.Value(ctxLoggerKey{}).(ctxLogger); ok && attached.log == zl {
+		own, other := l.resolve(), attached.logger.resolve()
+		if !own.mergeContext || attached.logger == l || other == own {
+			return other
+		}
+
+		l = own
+		extra := missingFields(l.fields, other.fields)
+		merged := l.derive(l.current().With().Fields(extra).Logger())
+		merged.fields = append(l.fields[:len(l.fields):len(l.fields)], extra...)
+		merged.group = other.group
+		return merged
+	}
+
+	if zl != zerolog.DefaultContextLogger && zl.GetLevel() != zerolog.Disabled {
+		return (&Logger{}).derive(*zl)
+	}
+
+	return l.resolve()
+}
+
+// missingFields returns the key/value pairs of fields that are not already in own,
+// such as the fields a logger derived from own added to it
+func missingFields(own, fields []interface{}) []interface{} {
+	if len(own) == 0 {
+		return fields
+	}
+
+	var missing []interface{}
+	for i := 0; i < len(fields); i += 2 {
+		if !hasField(own, fields[i], fields[i+1]) {
+			missing = append(missing, fields[i], fields[i+1])
+		}
 	}
+
+	return missing
+}
+
+// hasField reports whether the key/value pairs of fields contain the pair
+func hasField(fields []interface{}, key, value interface{}) bool {
+	for i := 0; i < len(fields); i += 2 {
+		if fields[i] == key && reflect.DeepEqual(fields[i+1], value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withFields returns a copy of the logger with the key/value pairs appended as fields
+func (l *Logger) withFields(kvs []interface{}) *Logger {
+	l = l.resolve()
+	kvs = l.groupKeys(kvs)
+
+	child := l.derive(l.current().With().Fields(kvs).Logger())
+	child.fields = append(l.fields[:len(l.fields):len(l.fields)], kvs...)
+	return child
+}
+
+// derive returns a logger with the configuration of the receiver that logs through log
+func (l *Logger) derive(log zerolog.Logger) *Logger {
+	child := &Logger{
+		fields:         l.fields,
+		group:          l.group,
+		mergeContext:   l.mergeContext,
+		stages:         l.stages,
+		traceExtractor: l.traceExtractor,
+		options:        l.options,
+		name:           l.name,
+		out:            l.output(),
+	}
+	child.log.Store(&log)
+
+	return child
+}
+
+// resolve returns the logger to log through: the receiver, or the current state of a named logger
+func (l *Logger) resolve() *Logger {
+	if l.named != nil {
+		return l.named.logger()
+	}
+
+	return l
+}
+
+// current returns the underlying zerolog logger to log through, a disabled logger for the zero value
+func (l *Logger) current() *zerolog.Logger {
+	if log := l.resolve().log.Load(); log != nil {
+		return log
+	}
+
+	return &disabledLogger
+}
+
+// output returns the output the writer stages of the logger wrap
+func (l *Logger) output() io.Writer {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.out
+}
+
+// update atomically replaces the underlying zerolog logger with the result of fn
+func (l *Logger) update(fn func(log zerolog.Logger) zerolog.Logger) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	log := fn(*l.current())
+	l.log.Store(&log)
+}
+
+// groupKeys qualifies the keys of the key/value pairs with the group of the logger
+func (l *Logger) groupKeys(kvs []interface{}) []interface{} {
+	if l.group == "" || len(kvs) == 0 {
+		return kvs
+	}
+
+	grouped := make([]interface{}, len(kvs))
+	for i := 0; i < len(kvs); i += 2 {
+		grouped[i] = l.group + kvs[i].(string)
+		grouped[i+1] = kvs[i+1]
+	}
+
+	return grouped
+}
+
+// newEvent starts an event on logger at the zerolog level matching the hlog level
+func newEvent(logger *zerolog.Logger, level hlog.Level) *zerolog.Event {
+	switch level {
+	case hlog.LevelTrace, hlog.LevelDebug:
+		return logger.Debug()
+	case hlog.LevelInfo:
+		return logger.Info()
+	case hlog.LevelNotice, hlog.LevelWarn:
+		return logger.Warn()
+	case hlog.LevelError:
+		return logger.Error()
+	case hlog.LevelFatal:
+		return logger.Fatal()
+	default:
+		return logger.Warn()
+	}
+}
+
+// wrapOutput applies the writer stages to the output of the named logger, the first stage being the first to see an event
+func wrapOutput(out io.Writer, stages []writerStage, logger string) io.Writer {
+	for i := len(stages) - 1; i >= 0; i-- {
+		out = stages[i](out, logger)
+	}
+
+	return out
+}
+
+func newLogger(log zerolog.Logger, out io.Writer, options []Opt) *Logger {
+	opts := newOptions(log, out, options)
+	log = opts.context.Logger()
+
+	if opts.metrics != nil && hasSampler(opts.sampler) {
+		log = log.Sample(opts.metrics.sampler(opts.sampler))
+	}
+
+	if len(opts.stages) > 0 {
+		if opts.out == nil {
+			opts.out = os.Stdout
+		}
+		log = log.Output(wrapOutput(opts.out, opts.stages, ""))
+	}
+
+	l := &Logger{
+		mergeContext:   opts.mergeContext,
+		stages:         opts.stages,
+		traceExtractor: opts.traceExtractor,
+		options:        options,
+		out:            opts.out,
+	}
+	l.log.Store(&log)
+
+	return l
 }
@@ -3,10 +3,13 @@ package zerolog
 import (
 	"bytes"
 	"context"
+	"errors"
+	"sync"
 	"testing"
 
 	"github.com/cloudwego/hertz/pkg/common/hlog"
 	"github.com/cloudwego/hertz/pkg/common/json"
+	"github.com/cloudwego/hertz/pkg/common/ut"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
@@ -48,9 +51,9 @@ func TestLoggerWithField(t *testing.T) {
 	b := &bytes.Buffer{}
 	l := New()
 	l.SetOutput(b)
-	l.WithField("service", "logging")
+	child := l.WithField("service", "logging")
 
-	l.Info("foobar")
+	child.Info("foobar")
 
 	type Log struct {
 		Level   string `json:"level"`
@@ -62,9 +65,102 @@ func TestLoggerWithField(t *testing.T) {
 
 	err := json.Unmarshal(b.Bytes(), log)
 
-	println(b.String())
 	assert.NoError(t, err)
 	assert.Equal(t, "logging", log.Service)
+
+	b.Reset()
+	l.Info("foobar")
+	assert.Equal(
+		t,
+		`{"level":"info","message":"foobar"}
+`,
+		b.String(),
+	)
+}
+
+func TestLoggerWith(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New()
+	l.SetOutput(b)
+
+	l.With("user", "alice", "order", 42).Info("foo")
+	assert.Equal(
+		t,
+		`{"level":"info","user":"alice","order":42,"message":"foo"}
+`,
+		b.String(),
+	)
+
+	b.Reset()
+	l.WithFields(map[string]interface{}{"port": 8080, "host": "localhost"}).Info("foo")
+	assert.Equal(
+		t,
+		`{"level":"info","host":"localhost","port":8080,"message":"foo"}
+`,
+		b.String(),
+	)
+
+	b.Reset()
+	l.Info("foo")
+	assert.Equal(
+		t,
+		`{"level":"info","message":"foo"}
+`,
+		b.String(),
+	)
+}
+
+func TestLoggerWithGroup(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New()
+	l.SetOutput(b)
+
+	child := l.WithField("service", "logging").WithGroup("user").With("id", 1).WithGroup("address")
+	child.Infow("foo", "city", "Stockholm")
+	assert.Equal(
+		t,
+		`{"level":"info","service":"logging","user.id":1,"user.address.city":"Stockholm","message":"foo"}
+`,
+		b.String(),
+	)
+
+	b.Reset()
+	l.Infow("foo", "city", "Stockholm")
+	assert.Equal(
+		t,
+		`{"level":"info","city":"Stockholm","message":"foo"}
+`,
+		b.String(),
+	)
+}
+
+func TestLoggerWithConcurrent(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithLevel(hlog.LevelInfo))
+	l.SetOutput(b)
+	hlog.SetLogger(l)
+
+	engine := newTestEngine(RequestID())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ut.PerformRequest(engine, "GET", "/users/42", nil)
+			GetLogger().WithField("worker", "w").WithGroup("g").With("k", "v")
+		}()
+	}
+	wg.Wait()
+
+	b.Reset()
+	GetLogger().Info("foo")
+	assert.Equal(
+		t,
+		`{"level":"info","message":"foo"}
+`,
+		b.String(),
+	)
 }
 
 func TestUnwrap(t *testing.T) {
@@ -76,6 +172,45 @@ func TestUnwrap(t *testing.T) {
 	assert.IsType(t, &zerolog.Logger{}, logger)
 }
 
+func TestUnwrapConcurrent(t *testing.T) {
+	b := &syncBuffer{}
+	l := New(WithOutput(b))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			l.Unwrap().UpdateContext(func(c zerolog.Context) zerolog.Context {
+				return c.Str("unwrapped", "yes")
+			})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			l.CtxInfof(context.Background(), "foo")
+		}
+	}()
+	wg.Wait()
+
+	assert.NotContains(t, b.String(), "unwrapped")
+}
+
+func TestZeroLogger(t *testing.T) {
+	var l Logger
+
+	assert.NotPanics(t, func() {
+		l.Info("foo")
+		l.Infow("foo", "key", "value")
+		l.CtxInfof(l.WithContext(context.Background()), "foo")
+		l.With("key", "value").WithGroup("group").Info("foo")
+		l.SetLevel(hlog.LevelDebug)
+		l.Debug("foo")
+	})
+	assert.Equal(t, hlog.LevelDebug, l.GetLevel())
+}
+
 func TestLog(t *testing.T) {
 	b := &bytes.Buffer{}
 	l := New()
@@ -198,7 +333,7 @@ func TestCtxTracef(t *testing.T) {
 	b := &bytes.Buffer{}
 	l := New()
 	l.SetOutput(b)
-	ctx := l.log.WithContext(context.Background())
+	ctx := l.log.Load().WithContext(context.Background())
 
 	l.CtxTracef(ctx, "foo%s", "bar")
 	assert.Equal(
@@ -214,7 +349,7 @@ func TestCtxDebugf(t *testing.T) {
 	b := &bytes.Buffer{}
 	l := New()
 	l.SetOutput(b)
-	ctx := l.log.WithContext(context.Background())
+	ctx := l.log.Load().WithContext(context.Background())
 
 	l.CtxDebugf(ctx, "foo%s", "bar")
 	assert.Equal(
@@ -230,7 +365,7 @@ func TestCtxInfof(t *testing.T) {
 	b := &bytes.Buffer{}
 	l := New()
 	l.SetOutput(b)
-	ctx := l.log.WithContext(context.Background())
+	ctx := l.log.Load().WithContext(context.Background())
 
 	l.CtxInfof(ctx, "foo%s", "bar")
 	assert.Equal(
@@ -246,7 +381,7 @@ func TestCtxNoticef(t *testing.T) {
 	b := &bytes.Buffer{}
 	l := New()
 	l.SetOutput(b)
-	ctx := l.log.WithContext(context.Background())
+	ctx := l.log.Load().WithContext(context.Background())
 
 	l.CtxNoticef(ctx, "foo%s", "bar")
 	assert.Equal(
@@ -262,7 +397,7 @@ func TestCtxWarnf(t *testing.T) {
 	b := &bytes.Buffer{}
 	l := New()
 	l.SetOutput(b)
-	ctx := l.log.WithContext(context.Background())
+	ctx := l.log.Load().WithContext(context.Background())
 
 	l.CtxWarnf(ctx, "foo%s", "bar")
 	assert.Equal(
@@ -278,7 +413,7 @@ func TestCtxErrorf(t *testing.T) {
 	b := &bytes.Buffer{}
 	l := New()
 	l.SetOutput(b)
-	ctx := l.log.WithContext(context.Background())
+	ctx := l.log.Load().WithContext(context.Background())
 
 	l.CtxErrorf(ctx, "foo%s", "bar")
 	assert.Equal(
@@ -294,11 +429,260 @@ func TestSetLevel(t *testing.T) {
 	l := New()
 
 	l.SetLevel(hlog.LevelDebug)
-	assert.Equal(t, l.log.GetLevel(), zerolog.DebugLevel)
+	assert.Equal(t, l.log.Load().GetLevel(), zerolog.DebugLevel)
 
 	l.SetLevel(hlog.LevelDebug)
-	assert.Equal(t, l.log.GetLevel(), zerolog.DebugLevel)
+	assert.Equal(t, l.log.Load().GetLevel(), zerolog.DebugLevel)
 
 	l.SetLevel(hlog.LevelError)
-	assert.Equal(t, l.log.GetLevel(), zerolog.ErrorLevel)
+	assert.Equal(t, l.log.Load().GetLevel(), zerolog.ErrorLevel)
+}
+
+type marshalerObject struct {
+	name string
+}
+
+func (o marshalerObject) MarshalZerologObject(e *zerolog.Event) {
+	e.Str("name", o.name)
+}
+
+func TestLogw(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New()
+	l.SetOutput(b)
+
+	l.Infow("foo", "user", "alice", "order", 42)
+	assert.Equal(
+		t,
+		`{"level":"info","user":"alice","order":42,"message":"foo"}
+`,
+		b.String(),
+	)
+
+	b.Reset()
+	l.Warnw("foo", 1, true, "object", marshalerObject{name: "bar"})
+	assert.Equal(
+		t,
+		`{"level":"warn","1":true,"object":{"name":"bar"},"message":"foo"}
+`,
+		b.String(),
+	)
+
+	b.Reset()
+	l.Errorw("foo", "attempt", 3, errors.New("failed"))
+	assert.Equal(
+		t,
+		`{"level":"error","attempt":3,"error":"failed","message":"foo"}
+`,
+		b.String(),
+	)
+
+	b.Reset()
+	l.Debugw("foo", "cause", errors.New("failed"), "dangling")
+	assert.Equal(
+		t,
+		`{"level":"debug","cause":"failed","!BADKEY":"dangling","message":"foo"}
+`,
+		b.String(),
+	)
+
+	b.Reset()
+	l.Tracew("foo")
+	assert.Equal(
+		t,
+		`{"level":"debug","message":"foo"}
+`,
+		b.String(),
+	)
+
+	b.Reset()
+	l.Noticew("foo", "key", "value")
+	assert.Equal(
+		t,
+		`{"level":"warn","key":"value","message":"foo"}
+`,
+		b.String(),
+	)
+}
+
+func TestCtxLogw(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New()
+	l.SetOutput(b)
+	ctx := l.log.Load().With().Str("request_id", "abc").Logger().WithContext(context.Background())
+
+	l.CtxInfow(ctx, "foo", "user", "alice")
+	assert.Equal(
+		t,
+		`{"level":"info","request_id":"abc","user":"alice","message":"foo"}
+`,
+		b.String(),
+	)
+
+	b.Reset()
+	l.CtxErrorw(ctx, "foo", errors.New("failed"))
+	assert.Equal(
+		t,
+		`{"level":"error","request_id":"abc","error":"failed","message":"foo"}
+`,
+		b.String(),
+	)
+
+	b.Reset()
+	l.CtxTracew(ctx, "foo")
+	l.CtxDebugw(ctx, "foo")
+	l.CtxNoticew(ctx, "foo")
+	l.CtxWarnw(ctx, "foo")
+	assert.Equal(
+		t,
+		`{"level":"debug","request_id":"abc","message":"foo"}
+{"level":"debug","request_id":"abc","message":"foo"}
+{"level":"warn","request_id":"abc","message":"foo"}
+{"level":"warn","request_id":"abc","message":"foo"}
+`,
+		b.String(),
+	)
+}
+
+func TestCtxLogfFallback(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithField("service", "logging"))
+	l.SetOutput(b)
+
+	l.CtxInfof(context.Background(), "foo%s", "bar")
+	assert.Equal(
+		t,
+		`{"level":"info","service":"logging","message":"foobar"}
+`,
+		b.String(),
+	)
+}
+
+func TestCtxLogfContextLogger(t *testing.T) {
+	b := &bytes.Buffer{}
+	other := &bytes.Buffer{}
+	l := New()
+	l.SetOutput(b)
+	ctx := From(zerolog.New(other)).WithField("request_id", "abc").WithContext(context.Background())
+
+	l.CtxInfof(ctx, "foo")
+	assert.Empty(t, b.String())
+	assert.Equal(
+		t,
+		`{"level":"info","request_id":"abc","message":"foo"}
+`,
+		other.String(),
+	)
+
+	other.Reset()
+	ctx = zerolog.New(other).With().Str("source", "zerolog").Logger().WithContext(ctx)
+	l.CtxInfof(ctx, "foo")
+	assert.Equal(
+		t,
+		`{"level":"info","source":"zerolog","message":"foo"}
+`,
+		other.String(),
+	)
+}
+
+func TestCtxLogfMerge(t *testing.T) {
+	b := &bytes.Buffer{}
+	other := &bytes.Buffer{}
+	l := New(WithContextMerge(), WithField("service", "logging"), WithLevel(hlog.LevelInfo))
+	l.SetOutput(b)
+	ctx := From(zerolog.New(other)).WithField("request_id", "abc").WithContext(context.Background())
+
+	l.CtxDebugf(ctx, "foo")
+	l.CtxInfof(ctx, "foo")
+	assert.Empty(t, other.String())
+	assert.Equal(
+		t,
+		`{"level":"info","service":"logging","request_id":"abc","message":"foo"}
+`,
+		b.String(),
+	)
+}
+
+func TestCtxLogfMergeDerived(t *testing.T) {
+	b := &bytes.Buffer{}
+	root := New(WithContextMerge(), WithOutput(b), WithLevel(hlog.LevelInfo))
+	base := root.With("svc", "x", "tags", []string{"a"})
+	ctx := base.With("req", "1").WithContext(context.Background())
+
+	base.CtxInfof(ctx, "foo")
+	base.WithField("user", "u").CtxInfow(ctx, "bar", "k", "v")
+	assert.Equal(
+		t,
+		`{"level":"info","svc":"x","tags":["a"],"req":"1","message":"foo"}
+{"level":"info","svc":"x","tags":["a"],"user":"u","req":"1","k":"v","message":"bar"}
+`,
+		b.String(),
+	)
+}
+
+type syncBuffer struct {
+	mu sync.Mutex
+	b  bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.String()
+}
+
+func TestSetLevelSetOutputConcurrent(t *testing.T) {
+	first := &syncBuffer{}
+	second := &syncBuffer{}
+	l := New(WithOutput(first))
+	ctx := l.WithField("request_id", "abc").WithContext(context.Background())
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					l.Info("foo")
+					l.Infow("foo", "key", "value")
+					l.CtxInfof(context.Background(), "foo")
+					l.CtxInfof(ctx, "foo")
+					l.WithField("key", "value").Debug("foo")
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 200; i++ {
+		if i%2 == 0 {
+			l.SetLevel(hlog.LevelError)
+			l.SetOutput(second)
+		} else {
+			l.SetLevel(hlog.LevelDebug)
+			l.SetOutput(first)
+		}
+	}
+	close(done)
+	wg.Wait()
+
+	l.SetLevel(hlog.LevelInfo)
+	l.SetOutput(second)
+	assert.Equal(t, zerolog.InfoLevel, l.Unwrap().GetLevel())
+
+	before := len(second.String())
+	l.Debug("foo")
+	l.Info("bar")
+	assert.Equal(t, `{"level":"info","message":"bar"}
+`, second.String()[before:])
 }
@@ -0,0 +1,293 @@
+package zerolog
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/rs/zerolog"
+)
+
+// PrometheusContentType is the content type of the Prometheus text exposition format served by Metrics.Handler
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// labelEscaper escapes label values of the Prometheus text format
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// noLevelLabel is the level label of the events written without level, such as with zerolog's Logger.Write
+const noLevelLabel = "none"
+
+// metricLevels are the levels counted for the root logger before any event is written,
+// so that rates of every level can be computed from the start
+var metricLevels = []zerolog.Level{
+	zerolog.TraceLevel,
+	zerolog.DebugLevel,
+	zerolog.InfoLevel,
+	zerolog.WarnLevel,
+	zerolog.ErrorLevel,
+	zerolog.FatalLevel,
+}
+
+var _ zerolog.LevelWriter = (*metricsWriter)(nil)
+
+type (
+	// DropCounter is implemented by writers that drop events, such as AsyncWriter
+	DropCounter interface {
+		Dropped() uint64
+	}
+
+	// eventKey identifies the counter of the events of a logger at a level
+	eventKey struct {
+		logger string
+		level  zerolog.Level
+	}
+
+	// metricsWriter counts the events written through it by logger name and level
+	metricsWriter struct {
+		metrics *Metrics
+		logger  string
+		out     io.Writer
+	}
+
+	// sampledCounter counts the events a sampler drops
+	sampledCounter struct {
+		sampler zerolog.Sampler
+		counter *uint64
+	}
+)
+
+// Metrics counts the events written by loggers per logger name and level, the events dropped by sampling per level
+// and the events dropped by tracked writers, and exposes them in Prometheus text format
+type Metrics struct {
+	events  sync.Map // eventKey -> *uint64
+	sampled sync.Map // zerolog.Level -> *uint64
+
+	mu       sync.Mutex
+	droppers map[string]DropCounter
+}
+
+// NewMetrics returns metrics with every counter at zero
+func NewMetrics() *Metrics {
+	m := &Metrics{droppers: map[string]DropCounter{}}
+	for _, level := range metricLevels {
+		m.events.Store(eventKey{logger: RootLoggerName, level: level}, new(uint64))
+	}
+
+	return m
+}
+
+// WithMetrics counts the events of the logger and of its named loggers in m, by the name of the logger
+// that wrote them. Events are counted as a writer stage, after the stages added before it have processed them.
+// Events dropped by the samplers of the logger are counted per level.
+func WithMetrics(m *Metrics) Opt {
+	return func(opts *Options) {
+		opts.metrics = m
+		opts.stages = append(opts.stages, m.namedWriter)
+	}
+}
+
+// Writer returns a writer that counts events as written by the root logger before writing them to out
+func (m *Metrics) Writer(out io.Writer) io.Writer {
+	return m.namedWriter(out, "")
+}
+
+// namedWriter returns a writer that counts events as written by the named logger before writing them to out
+func (m *Metrics) namedWriter(out io.Writer, logger string) io.Writer {
+	if logger == "" {
+		logger = RootLoggerName
+	}
+
+	return &metricsWriter{metrics: m, logger: logger, out: out}
+}
+
+// TrackDropped exposes the number of events dropped by a writer, such as an AsyncWriter, under the given name
+func (m *Metrics) TrackDropped(name string, counter DropCounter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.droppers[name] = counter
+}
+
+// Events returns the number of events written by the named logger at level.
+// The logger without name is reported as RootLoggerName.
+func (m *Metrics) Events(logger string, level hlog.Level) uint64 {
+	if logger == "" {
+		logger = RootLoggerName
+	}
+
+	if counter, ok := m.events.Load(eventKey{logger: logger, level: matchHlogLevel(level)}); ok {
+		return atomic.LoadUint64(counter.(*uint64))
+	}
+
+	return 0
+}
+
+// Sampled returns the number of events dropped by sampling at level
+func (m *Metrics) Sampled(level hlog.Level) uint64 {
+	if counter, ok := m.sampled.Load(matchHlogLevel(level)); ok {
+		return atomic.LoadUint64(counter.(*uint64))
+	}
+
+	return 0
+}
+
+// Handler returns a handler serving the metrics in Prometheus text format
+func (m *Metrics) Handler() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		var b bytes.Buffer
+		_ = m.WritePrometheus(&b)
+
+		c.Data(consts.StatusOK, PrometheusContentType, b.Bytes())
+	}
+}
+
+// WritePrometheus writes the metrics to w in Prometheus text format
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	var events []eventKey
+	m.events.Range(func(key, _ interface{}) bool {
+		events = append(events, key.(eventKey))
+		return true
+	})
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].logger != events[j].logger {
+			return events[i].logger < events[j].logger
+		}
+		return events[i].level < events[j].level
+	})
+
+	writeMetricHeader(bw, "log_events_total", "Events written by loggers.")
+	for _, key := range events {
+		counter, _ := m.events.Load(key)
+		writeMetric(bw, "log_events_total", atomic.LoadUint64(counter.(*uint64)),
+			"logger", key.logger, "level", levelLabel(key.level))
+	}
+
+	var sampled []zerolog.Level
+	m.sampled.Range(func(key, _ interface{}) bool {
+		sampled = append(sampled, key.(zerolog.Level))
+		return true
+	})
+	sort.Slice(sampled, func(i, j int) bool { return sampled[i] < sampled[j] })
+
+	writeMetricHeader(bw, "log_events_sampled_total", "Events dropped by sampling.")
+	for _, level := range sampled {
+		counter, _ := m.sampled.Load(level)
+		writeMetric(bw, "log_events_sampled_total", atomic.LoadUint64(counter.(*uint64)), "level", level.String())
+	}
+
+	m.mu.Lock()
+	names := make([]string, 0, len(m.droppers))
+	for name := range m.droppers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	writeMetricHeader(bw, "log_events_dropped_total", "Events dropped by writers.")
+	for _, name := range names {
+		writeMetric(bw, "log_events_dropped_total", m.droppers[name].Dropped(), "writer", name)
+	}
+	m.mu.Unlock()
+
+	return bw.Flush()
+}
+
+// count increments the counter of the events of logger at level
+func (m *Metrics) count(logger string, level zerolog.Level) {
+	key := eventKey{logger: logger, level: level}
+
+	counter, ok := m.events.Load(key)
+	if !ok {
+		counter, _ = m.events.LoadOrStore(key, new(uint64))
+	}
+	atomic.AddUint64(counter.(*uint64), 1)
+}
+
+// sampler wraps the samplers of s to count the events they drop
+func (m *Metrics) sampler(s zerolog.LevelSampler) zerolog.LevelSampler {
+	return zerolog.LevelSampler{
+		TraceSampler: m.countSampled(zerolog.TraceLevel, s.TraceSampler),
+		DebugSampler: m.countSampled(zerolog.DebugLevel, s.DebugSampler),
+		InfoSampler:  m.countSampled(zerolog.InfoLevel, s.InfoSampler),
+		WarnSampler:  m.countSampled(zerolog.WarnLevel, s.WarnSampler),
+		ErrorSampler: m.countSampled(zerolog.ErrorLevel, s.ErrorSampler),
+	}
+}
+
+func (m *Metrics) countSampled(level zerolog.Level, sampler zerolog.Sampler) zerolog.Sampler {
+	if sampler == nil {
+		return nil
+	}
+
+	counter, _ := m.sampled.LoadOrStore(level, new(uint64))
+	return &sampledCounter{sampler: sampler, counter: counter.(*uint64)}
+}
+
+func (s *sampledCounter) Sample(lvl zerolog.Level) bool {
+	if s.sampler.Sample(lvl) {
+		return true
+	}
+
+	atomic.AddUint64(s.counter, 1)
+	return false
+}
+
+func (w *metricsWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *metricsWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.metrics.count(w.logger, level)
+
+	if lw, ok := w.out.(zerolog.LevelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+
+	return w.out.Write(p)
+}
+
+// hasSampler reports whether any level of s is sampled
+func hasSampler(s zerolog.LevelSampler) bool {
+	return s.TraceSampler != nil || s.DebugSampler != nil || s.InfoSampler != nil ||
+		s.WarnSampler != nil || s.ErrorSampler != nil
+}
+
+// levelLabel returns the label of level, which zerolog leaves empty for events without level
+func levelLabel(level zerolog.Level) string {
+	if level == zerolog.NoLevel {
+		return noLevelLabel
+	}
+
+	return level.String()
+}
+
+func writeMetricHeader(w *bufio.Writer, name, help string) {
+	_, _ = w.WriteString("# HELP " + name + " " + help + "\n# TYPE " + name + " counter\n")
+}
+
+// writeMetric writes a sample of the metric with the label name/value pairs
+func writeMetric(w *bufio.Writer, name string, value uint64, labels ...string) {
+	_, _ = w.WriteString(name)
+	for i := 0; i < len(labels); i += 2 {
+		if i == 0 {
+			_ = w.WriteByte('{')
+		} else {
+			_ = w.WriteByte(',')
+		}
+		_, _ = w.WriteString(labels[i] + `="` + labelEscaper.Replace(labels[i+1]) + `"`)
+	}
+	if len(labels) > 0 {
+		_ = w.WriteByte('}')
+	}
+	_, _ = w.WriteString(" " + strconv.FormatUint(value, 10) + "\n")
+}
@@ -0,0 +1,122 @@
+package zerolog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/common/config"
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/cloudwego/hertz/pkg/route"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+type fixedDropCounter uint64
+
+func (c fixedDropCounter) Dropped() uint64 {
+	return uint64(c)
+}
+
+func TestWithMetrics(t *testing.T) {
+	m := NewMetrics()
+	l := New(WithOutput(&bytes.Buffer{}), WithLevel(hlog.LevelDebug), WithMetrics(m))
+	registry := NewRegistry(l)
+
+	l.Info("foo")
+	l.Errorf("failed %d", 1)
+	registry.Named("billing").Warnw("slow", "user", map[string]string{"logger": "nested"})
+	registry.Named("billing").Error("failed")
+	registry.Named(`say "hi"`).Info("hi")
+	registry.Named("billing").Debug("dropped by level")
+	l.WithField("k", "v").Debug("child")
+	l.Infow("spoofed", "logger", "billing")
+	registry.Named("billing").With("k", "v").Info("child")
+
+	assert.Equal(t, uint64(2), m.Events("", hlog.LevelInfo))
+	assert.Equal(t, uint64(1), m.Events("billing", hlog.LevelInfo))
+	assert.Equal(t, uint64(1), m.Events(RootLoggerName, hlog.LevelDebug))
+	assert.Equal(t, uint64(1), m.Events("billing", hlog.LevelWarn))
+	assert.Equal(t, uint64(1), m.Events("billing", hlog.LevelError))
+	assert.Equal(t, uint64(1), m.Events("billing", hlog.LevelDebug))
+	assert.Equal(t, uint64(1), m.Events(`say "hi"`, hlog.LevelInfo))
+	assert.Equal(t, uint64(1), m.Events(RootLoggerName, hlog.LevelError))
+	assert.Equal(t, uint64(0), m.Events("unknown", hlog.LevelInfo))
+}
+
+func TestMetricsSampled(t *testing.T) {
+	m := NewMetrics()
+	l := New(WithOutput(&bytes.Buffer{}), WithLevel(hlog.LevelDebug), WithEveryNSampling(2), WithMetrics(m))
+
+	for i := 0; i < 4; i++ {
+		l.Debug("tick")
+		l.Warn("tock")
+	}
+
+	assert.Equal(t, uint64(2), m.Events("", hlog.LevelDebug))
+	assert.Equal(t, uint64(2), m.Sampled(hlog.LevelDebug))
+	assert.Equal(t, uint64(4), m.Events("", hlog.LevelWarn))
+	assert.Equal(t, uint64(0), m.Sampled(hlog.LevelWarn))
+}
+
+func TestMetricsKeepsSamplerOfFrom(t *testing.T) {
+	m := NewMetrics()
+	b := &bytes.Buffer{}
+	l := From(zerolog.New(b).Sample(&zerolog.BasicSampler{N: 2}), WithOutput(b), WithMetrics(m))
+
+	for i := 0; i < 4; i++ {
+		l.Warn("tock")
+	}
+
+	assert.Equal(t, uint64(2), m.Events("", hlog.LevelWarn))
+}
+
+func TestMetricsWritePrometheus(t *testing.T) {
+	m := NewMetrics()
+	m.TrackDropped("async", fixedDropCounter(3))
+	l := New(WithOutput(&bytes.Buffer{}), WithBurstSampling(1, time.Hour), WithMetrics(m))
+	registry := NewRegistry(l)
+
+	l.Error("failed")
+	registry.Named("bill\\ing").Info("foo")
+	registry.Named("bill\\ing").Info("foo")
+	l.Unwrap().Log().Msg("raw")
+
+	b := &bytes.Buffer{}
+	assert.NoError(t, m.WritePrometheus(b))
+	assert.Equal(t, `# HELP log_events_total Events written by loggers.
+# TYPE log_events_total counter
+log_events_total{logger="bill\\ing",level="info"} 1
+log_events_total{logger="root",level="trace"} 0
+log_events_total{logger="root",level="debug"} 0
+log_events_total{logger="root",level="info"} 0
+log_events_total{logger="root",level="warn"} 0
+log_events_total{logger="root",level="error"} 1
+log_events_total{logger="root",level="fatal"} 0
+log_events_total{logger="root",level="none"} 1
+# HELP log_events_sampled_total Events dropped by sampling.
+# TYPE log_events_sampled_total counter
+log_events_sampled_total{level="trace"} 0
+log_events_sampled_total{level="debug"} 0
+log_events_sampled_total{level="info"} 1
+# HELP log_events_dropped_total Events dropped by writers.
+# TYPE log_events_dropped_total counter
+log_events_dropped_total{writer="async"} 3
+`, b.String())
+}
+
+func TestMetricsHandler(t *testing.T) {
+	m := NewMetrics()
+	l := New(WithOutput(&bytes.Buffer{}), WithMetrics(m))
+	l.Warn("foo")
+
+	router := route.NewEngine(config.NewOptions([]config.Option{}))
+	router.GET("/metrics", m.Handler())
+
+	w := ut.PerformRequest(router, "GET", "/metrics", nil)
+	resp := w.Result()
+	assert.Equal(t, 200, resp.StatusCode())
+	assert.Equal(t, PrometheusContentType, string(resp.Header.ContentType()))
+	assert.Contains(t, string(resp.Body()), "log_events_total{logger=\"root\",level=\"warn\"} 1\n")
+}
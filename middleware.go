@@ -0,0 +1,217 @@
+package zerolog
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/rs/zerolog"
+)
+
+// AccessLogField is a bit set selecting which fields the access log middleware emits
+type AccessLogField uint
+
+const (
+	FieldRemoteIP AccessLogField = 1 << iota
+	FieldMethod
+	FieldPath
+	FieldRoute
+	FieldStatus
+	FieldLatency
+	FieldBytesIn
+	FieldBytesOut
+	FieldUserAgent
+	FieldRequestID
+
+	// DefaultAccessLogFields are the fields emitted when WithAccessLogFields is not used
+	DefaultAccessLogFields = FieldRemoteIP | FieldMethod | FieldPath | FieldStatus | FieldLatency | FieldUserAgent | FieldRequestID
+
+	// AllAccessLogFields enables every access log field
+	AllAccessLogFields = FieldRemoteIP | FieldMethod | FieldPath | FieldRoute | FieldStatus |
+		FieldLatency | FieldBytesIn | FieldBytesOut | FieldUserAgent | FieldRequestID
+)
+
+type (
+	AccessLogOptions struct {
+		logger    *Logger
+		fields    AccessLogField
+		message   string
+		levelFunc func(status int) hlog.Level
+		skipPaths map[string]struct{}
+		skippers  []func(ctx context.Context, c *app.RequestContext) bool
+		bodies    bodyCapture
+		format    *accessLogFormat
+	}
+
+	AccessLogOpt func(opts *AccessLogOptions)
+)
+
+func newAccessLogOptions(options []AccessLogOpt) *AccessLogOptions {
+	opts := &AccessLogOptions{
+		fields:    DefaultAccessLogFields,
+		message:   "request processed",
+		levelFunc: StatusLevels(hlog.LevelInfo, hlog.LevelWarn, hlog.LevelError),
+		skipPaths: map[string]struct{}{},
+		bodies: bodyCapture{
+			limit:        DefaultBodyLimit,
+			contentTypes: DefaultBodyContentTypes,
+		},
+	}
+
+	for _, set := range options {
+		set(opts)
+	}
+
+	return opts
+}
+
+// WithAccessLogger allows to specify the logger used by the access log middleware.
+// By default, the logger returned by GetLogger at request time is used.
+func WithAccessLogger(logger *Logger) AccessLogOpt {
+	return func(opts *AccessLogOptions) {
+		opts.logger = logger
+	}
+}
+
+// WithAccessLogFields allows to specify which fields are emitted. By default, it is set to DefaultAccessLogFields.
+func WithAccessLogFields(fields AccessLogField) AccessLogOpt {
+	return func(opts *AccessLogOptions) {
+		opts.fields = fields
+	}
+}
+
+// WithAccessLogMessage allows to specify the message of the access log entry. By default, it is set to "request processed".
+func WithAccessLogMessage(message string) AccessLogOpt {
+	return func(opts *AccessLogOptions) {
+		opts.message = message
+	}
+}
+
+// WithAccessLogLevels allows to specify the level used for each status class:
+// 1xx-3xx use success, 4xx use clientError and 5xx use serverError.
+func WithAccessLogLevels(success, clientError, serverError hlog.Level) AccessLogOpt {
+	return WithAccessLogLevelFunc(StatusLevels(success, clientError, serverError))
+}
+
+// WithAccessLogLevelFunc allows to specify a function selecting the level from the response status code
+func WithAccessLogLevelFunc(fn func(status int) hlog.Level) AccessLogOpt {
+	return func(opts *AccessLogOptions) {
+		opts.levelFunc = fn
+	}
+}
+
+// WithAccessLogSkipPaths allows to specify request paths that are never logged, e.g. health checks
+func WithAccessLogSkipPaths(paths ...string) AccessLogOpt {
+	return func(opts *AccessLogOptions) {
+		for _, path := range paths {
+			opts.skipPaths[path] = struct{}{}
+		}
+	}
+}
+
+// WithAccessLogSkipper adds a function that skips logging of a request when it returns true.
+// Skippers are evaluated after the request has been handled so the response is available.
+func WithAccessLogSkipper(skipper func(ctx context.Context, c *app.RequestContext) bool) AccessLogOpt {
+	return func(opts *AccessLogOptions) {
+		opts.skippers = append(opts.skippers, skipper)
+	}
+}
+
+// StatusLevels returns a level function mapping status classes to levels
+func StatusLevels(success, clientError, serverError hlog.Level) func(status int) hlog.Level {
+	return func(status int) hlog.Level {
+		switch {
+		case status >= 500:
+			return serverError
+		case status >= 400:
+			return clientError
+		default:
+			return success
+		}
+	}
+}
+
+// AccessLogger returns a middleware that logs every request processed by the Hertz server
+func AccessLogger(options ...AccessLogOpt) app.HandlerFunc {
+	opts := newAccessLogOptions(options)
+
+	return func(ctx context.Context, c *app.RequestContext) {
+		if _, skip := opts.skipPaths[string(c.Path())]; skip {
+			c.Next(ctx)
+			return
+		}
+
+		start := time.Now()
+		c.Next(ctx)
+		latency := time.Since(start)
+
+		for _, skipper := range opts.skippers {
+			if skipper(ctx, c) {
+				return
+			}
+		}
+
+		if opts.format != nil {
+			opts.format.write(&accessLogRecord{c: c, start: start, latency: latency, status: c.Response.StatusCode()})
+			return
+		}
+
+		logger := opts.logger
+		if logger == nil {
+			logger = GetLogger()
+		}
+		if logger == nil {
+			return
+		}
+
+		status := c.Response.StatusCode()
+		event := logger.current().WithLevel(matchHlogLevel(opts.levelFunc(status)))
+		if event == nil {
+			return
+		}
+
+		opts.appendFields(event, c, status, latency)
+		event.Msg(opts.message)
+	}
+}
+
+func (opts *AccessLogOptions) appendFields(e *zerolog.Event, c *app.RequestContext, status int, latency time.Duration) {
+	fields := opts.fields
+
+	if fields&FieldRemoteIP != 0 {
+		e.Str("remote_ip", c.ClientIP())
+	}
+	if fields&FieldMethod != 0 {
+		e.Bytes("method", c.Method())
+	}
+	if fields&FieldPath != 0 {
+		e.Bytes("path", c.Path())
+	}
+	if fields&FieldRoute != 0 {
+		e.Str("route", c.FullPath())
+	}
+	if fields&FieldStatus != 0 {
+		e.Int("status", status)
+	}
+	if fields&FieldLatency != 0 {
+		e.Dur("latency", latency)
+		e.Str("latency_human", latency.String())
+	}
+	if fields&FieldBytesIn != 0 {
+		e.Int("bytes_in", len(c.Request.Body()))
+	}
+	if fields&FieldBytesOut != 0 {
+		e.Int("bytes_out", len(c.Response.Body()))
+	}
+	if fields&FieldUserAgent != 0 {
+		e.Bytes("user_agent", c.UserAgent())
+	}
+	if fields&FieldRequestID != 0 {
+		if id := GetRequestID(c); id != "" {
+			e.Str(requestIDField(c), id)
+		}
+	}
+
+	opts.bodies.appendFields(e, c)
+}
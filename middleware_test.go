@@ -0,0 +1,154 @@
+package zerolog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/config"
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/cloudwego/hertz/pkg/common/json"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/cloudwego/hertz/pkg/route"
+	"github.com/stretchr/testify/assert"
+)
+
+type AccessLog struct {
+	Level     string   `json:"level"`
+	RemoteIP  string   `json:"remote_ip"`
+	Method    string   `json:"method"`
+	Path      string   `json:"path"`
+	Route     string   `json:"route"`
+	Status    int      `json:"status"`
+	Latency   *float64 `json:"latency"`
+	BytesIn   int      `json:"bytes_in"`
+	BytesOut  int      `json:"bytes_out"`
+	UserAgent string   `json:"user_agent"`
+	Message   string   `json:"message"`
+}
+
+func newTestEngine(handlers ...app.HandlerFunc) *route.Engine {
+	engine := route.NewEngine(config.NewOptions([]config.Option{}))
+	engine.Use(handlers...)
+	engine.GET("/users/:id", func(ctx context.Context, c *app.RequestContext) {
+		c.String(200, "user %s", c.Param("id"))
+	})
+	engine.POST("/users", func(ctx context.Context, c *app.RequestContext) {
+		c.String(400, "bad request")
+	})
+	engine.GET("/fail", func(ctx context.Context, c *app.RequestContext) {
+		c.String(500, "failed")
+	})
+	engine.GET("/health", func(ctx context.Context, c *app.RequestContext) {
+		c.String(200, "ok")
+	})
+	return engine
+}
+
+func TestAccessLogger(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithLevel(hlog.LevelInfo))
+	engine := newTestEngine(AccessLogger(WithAccessLogger(l)))
+
+	ut.PerformRequest(engine, "GET", "/users/42", nil, ut.Header{Key: "User-Agent", Value: "test-agent"})
+
+	log := &AccessLog{}
+	err := json.Unmarshal(b.Bytes(), log)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "info", log.Level)
+	assert.Equal(t, "GET", log.Method)
+	assert.Equal(t, "/users/42", log.Path)
+	assert.Equal(t, 200, log.Status)
+	assert.Equal(t, "test-agent", log.UserAgent)
+	assert.NotNil(t, log.Latency)
+	assert.Equal(t, "request processed", log.Message)
+	assert.Empty(t, log.Route)
+}
+
+func TestAccessLoggerGetLogger(t *testing.T) {
+	b := &bytes.Buffer{}
+	hlog.SetLogger(New(WithOutput(b), WithLevel(hlog.LevelInfo)))
+	engine := newTestEngine(AccessLogger())
+
+	ut.PerformRequest(engine, "GET", "/users/42", nil)
+
+	assert.Contains(t, b.String(), `"path":"/users/42"`)
+}
+
+func TestAccessLoggerFields(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithLevel(hlog.LevelInfo))
+	engine := newTestEngine(AccessLogger(
+		WithAccessLogger(l),
+		WithAccessLogFields(FieldRoute|FieldBytesIn|FieldBytesOut),
+		WithAccessLogMessage("access"),
+	))
+
+	ut.PerformRequest(engine, "GET", "/users/42", nil)
+
+	log := &AccessLog{}
+	err := json.Unmarshal(b.Bytes(), log)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/users/:id", log.Route)
+	assert.Equal(t, 0, log.BytesIn)
+	assert.Equal(t, len("user 42"), log.BytesOut)
+	assert.Equal(t, "access", log.Message)
+	assert.Empty(t, log.Path)
+	assert.Empty(t, log.Method)
+	assert.Nil(t, log.Latency)
+}
+
+func TestAccessLoggerLevels(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithLevel(hlog.LevelInfo))
+	engine := newTestEngine(AccessLogger(WithAccessLogger(l)))
+
+	ut.PerformRequest(engine, "POST", "/users", nil)
+	assert.Contains(t, b.String(), `"level":"warn"`)
+
+	b.Reset()
+	ut.PerformRequest(engine, "GET", "/fail", nil)
+	assert.Contains(t, b.String(), `"level":"error"`)
+
+	b.Reset()
+	engine = newTestEngine(AccessLogger(
+		WithAccessLogger(l),
+		WithAccessLogLevels(hlog.LevelDebug, hlog.LevelInfo, hlog.LevelInfo),
+	))
+	ut.PerformRequest(engine, "GET", "/users/42", nil)
+	assert.Empty(t, b.String())
+
+	ut.PerformRequest(engine, "GET", "/fail", nil)
+	assert.Contains(t, b.String(), `"level":"info"`)
+}
+
+func TestAccessLoggerSkip(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithLevel(hlog.LevelInfo))
+	engine := newTestEngine(AccessLogger(
+		WithAccessLogger(l),
+		WithAccessLogSkipPaths("/health"),
+		WithAccessLogSkipper(func(ctx context.Context, c *app.RequestContext) bool {
+			return c.Response.StatusCode() == 400
+		}),
+	))
+
+	ut.PerformRequest(engine, "GET", "/health", nil)
+	ut.PerformRequest(engine, "POST", "/users", nil)
+	assert.Empty(t, b.String())
+
+	ut.PerformRequest(engine, "GET", "/fail", nil)
+	assert.Contains(t, b.String(), `"path":"/fail"`)
+}
+
+func TestStatusLevels(t *testing.T) {
+	levels := StatusLevels(hlog.LevelInfo, hlog.LevelWarn, hlog.LevelError)
+
+	assert.Equal(t, hlog.LevelInfo, levels(200))
+	assert.Equal(t, hlog.LevelInfo, levels(302))
+	assert.Equal(t, hlog.LevelWarn, levels(404))
+	assert.Equal(t, hlog.LevelError, levels(503))
+}
@@ -2,6 +2,7 @@ package zerolog
 
 import (
 	"io"
+	"time"
 
 	"github.com/cloudwego/hertz/pkg/common/hlog"
 	"github.com/rs/zerolog"
@@ -9,17 +10,36 @@ import (
 
 type (
 	Options struct {
-		context zerolog.Context
-		level   zerolog.Level
+		context        zerolog.Context
+		level          zerolog.Level
+		sampler        zerolog.LevelSampler
+		mergeContext   bool
+		out            io.Writer
+		stages         []writerStage
+		traceExtractor TraceExtractor
+		metrics        *Metrics
 	}
 
 	Opt func(opts *Options)
+
+	// writerStage wraps the output of a logger to process encoded events before they are written.
+	// logger is the name of the named logger the output is built for, empty for other loggers.
+	writerStage func(out io.Writer, logger string) io.Writer
 )
 
-func newOptions(log zerolog.Logger, options []Opt) *Options {
+// unnamedStage returns a writer stage wrapping the output of every logger with wrap, whatever its name
+func unnamedStage(wrap func(out io.Writer) io.Writer) writerStage {
+	return func(out io.Writer, _ string) io.Writer {
+		return wrap(out)
+	}
+}
+
+func newOptions(log zerolog.Logger, out io.Writer, options []Opt) *Options {
 	opts := &Options{
-		context: log.With(),
-		level:   log.GetLevel(),
+		context:        log.With(),
+		level:          log.GetLevel(),
+		out:            out,
+		traceExtractor: TraceContextFromContext,
 	}
 
 	for _, set := range options {
@@ -33,6 +53,7 @@ func newOptions(log zerolog.Logger, options []Opt) *Options {
 func WithOutput(out io.Writer) Opt {
 	return func(opts *Options) {
 		opts.context = opts.context.Logger().Output(out).With()
+		opts.out = out
 	}
 }
 
@@ -94,3 +115,70 @@ func WithHookFunc(hook zerolog.HookFunc) Opt {
 		opts.context = opts.context.Logger().Hook(hook).With()
 	}
 }
+
+// WithContextMerge makes the Ctx methods log through the logger's own output, level, hooks and fields,
+// with the fields of a Logger associated with the context layered on top.
+// By default, a Logger associated with the context is used as is.
+func WithContextMerge() Opt {
+	return func(opts *Options) {
+		opts.mergeContext = true
+	}
+}
+
+// WithSampler allows to specify a sampler applied to every level
+func WithSampler(sampler zerolog.Sampler) Opt {
+	return func(opts *Options) {
+		opts.sampler = zerolog.LevelSampler{
+			TraceSampler: sampler,
+			DebugSampler: sampler,
+			InfoSampler:  sampler,
+			WarnSampler:  sampler,
+			ErrorSampler: sampler,
+		}
+		opts.context = opts.context.Logger().Sample(opts.sampler).With()
+	}
+}
+
+// WithLevelSampler allows to specify a sampler applied to a single level, replacing a sampler previously set for it
+func WithLevelSampler(level hlog.Level, sampler zerolog.Sampler) Opt {
+	lvl := matchHlogLevel(level)
+	return func(opts *Options) {
+		switch lvl {
+		case zerolog.TraceLevel:
+			opts.sampler.TraceSampler = sampler
+		case zerolog.DebugLevel:
+			opts.sampler.DebugSampler = sampler
+		case zerolog.InfoLevel:
+			opts.sampler.InfoSampler = sampler
+		case zerolog.WarnLevel:
+			opts.sampler.WarnSampler = sampler
+		case zerolog.ErrorLevel:
+			opts.sampler.ErrorSampler = sampler
+		default:
+			return // fatal logs are never sampled
+		}
+		opts.context = opts.context.Logger().Sample(opts.sampler).With()
+	}
+}
+
+// WithBurstSampling allows burst logs per period at trace, debug and info level and drops the rest.
+// Warnings and errors always pass.
+func WithBurstSampling(burst uint32, period time.Duration) Opt {
+	return withVerboseSampler(&zerolog.BurstSampler{Burst: burst, Period: period})
+}
+
+// WithEveryNSampling allows one in every n logs at trace, debug and info level and drops the rest.
+// Warnings and errors always pass.
+func WithEveryNSampling(n uint32) Opt {
+	return withVerboseSampler(&zerolog.BasicSampler{N: n})
+}
+
+// withVerboseSampler shares a sampler between the trace, debug and info levels
+func withVerboseSampler(sampler zerolog.Sampler) Opt {
+	return func(opts *Options) {
+		opts.sampler.TraceSampler = sampler
+		opts.sampler.DebugSampler = sampler
+		opts.sampler.InfoSampler = sampler
+		opts.context = opts.context.Logger().Sample(opts.sampler).With()
+	}
+}
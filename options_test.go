@@ -203,3 +203,74 @@ func TestWithFormattedTimestamp(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotEmpty(t, log.Time)
 }
+
+func TestWithSampler(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithSampler(&zerolog.BasicSampler{N: 2}))
+
+	for i := 0; i < 4; i++ {
+		l.Error("foo")
+	}
+
+	assert.Equal(t, 2, strings.Count(b.String(), "\n"))
+}
+
+func TestWithLevelSampler(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(
+		WithOutput(b),
+		WithLevelSampler(hlog.LevelDebug, &zerolog.BasicSampler{N: 2}),
+		WithLevelSampler(hlog.LevelInfo, &zerolog.BasicSampler{N: 4}),
+	)
+
+	for i := 0; i < 4; i++ {
+		l.Debug("debug")
+		l.Info("info")
+		l.Warn("warn")
+	}
+
+	assert.Equal(t, 2, strings.Count(b.String(), `"debug"}`))
+	assert.Equal(t, 1, strings.Count(b.String(), `"info"}`))
+	assert.Equal(t, 4, strings.Count(b.String(), `"warn"}`))
+}
+
+func TestWithBurstSampling(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithBurstSampling(2, time.Hour))
+
+	for i := 0; i < 5; i++ {
+		l.Info("info")
+		l.Errorf("error %d", i)
+	}
+
+	assert.Equal(t, 2, strings.Count(b.String(), `"info"}`))
+	assert.Equal(t, 5, strings.Count(b.String(), `"level":"error"`))
+}
+
+func TestWithEveryNSampling(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithEveryNSampling(3))
+
+	for i := 0; i < 6; i++ {
+		l.Debug("debug")
+		l.Warn("warn")
+	}
+
+	assert.Equal(t, 2, strings.Count(b.String(), `"debug"}`))
+	assert.Equal(t, 6, strings.Count(b.String(), `"warn"}`))
+}
+
+func TestSamplingPreserved(t *testing.T) {
+	b := &bytes.Buffer{}
+	zl := zerolog.New(b).Sample(&zerolog.BasicSampler{N: 2})
+	l := From(zl, WithField("service", "logging"), WithTimestamp())
+	l.SetLevel(hlog.LevelDebug)
+	l.SetOutput(b)
+	child := l.With("key", "value")
+
+	for i := 0; i < 4; i++ {
+		child.Info("foo")
+	}
+
+	assert.Equal(t, 2, strings.Count(b.String(), "\n"))
+}
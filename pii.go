@@ -0,0 +1,142 @@
+package zerolog
+
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+// PIIDetector finds personal data in strings: the matches of Pattern for which Valid, when set, returns true
+type PIIDetector struct {
+	Name    string
+	Pattern *regexp.Regexp
+	Valid   func(match string) bool
+}
+
+var (
+	// EmailDetector finds email addresses
+	EmailDetector = PIIDetector{
+		Name:    "email",
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`),
+	}
+
+	// IBANDetector finds international bank account numbers with a valid check digits
+	IBANDetector = PIIDetector{
+		Name:    "iban",
+		Pattern: regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?\b`),
+		Valid:   validIBAN,
+	}
+
+	// CardNumberDetector finds payment card numbers of 13 to 19 digits, optionally grouped by spaces or dashes,
+	// that pass the Luhn check
+	CardNumberDetector = PIIDetector{
+		Name:    "card_number",
+		Pattern: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		Valid:   validLuhn,
+	}
+
+	// PhoneDetector finds international phone numbers starting with '+' and North American numbers
+	// written with separators, e.g. "+44 20 7946 0958" or "(555) 123-4567"
+	PhoneDetector = PIIDetector{
+		Name: "phone",
+		Pattern: regexp.MustCompile(
+			`\+\d{1,3}(?:[ .-]?\(?\d{1,4}\)?){2,5}\b|(?:\(\d{3}\) ?|\b\d{3}[ .-])\d{3}[ .-]\d{4}\b`),
+	}
+
+	// IPv4Detector finds IPv4 addresses
+	IPv4Detector = PIIDetector{
+		Name:    "ipv4",
+		Pattern: regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`),
+		Valid:   validIP,
+	}
+
+	// IPv6Detector finds IPv6 addresses
+	IPv6Detector = PIIDetector{
+		Name:    "ipv6",
+		Pattern: regexp.MustCompile(`[0-9A-Fa-f]*:[0-9A-Fa-f]*:[0-9A-Fa-f:.]*[0-9A-Fa-f]`),
+		Valid:   validIP,
+	}
+)
+
+// DefaultPIIDetectors returns the built-in detectors, in the order they are applied
+func DefaultPIIDetectors() []PIIDetector {
+	return []PIIDetector{EmailDetector, IBANDetector, CardNumberDetector, PhoneDetector, IPv6Detector, IPv4Detector}
+}
+
+// WithPIIDetectors masks the personal data found by the detectors in the message and in every string field,
+// at any depth. The level, timestamp and caller fields are not scanned.
+func WithPIIDetectors(detectors ...PIIDetector) RedactOpt {
+	return func(opts *RedactOptions) {
+		opts.detectors = append(opts.detectors, detectors...)
+	}
+}
+
+// WithPIIMasking masks the personal data found by the detectors before events are written to the output of the logger.
+// By default, DefaultPIIDetectors are used. It is a shorthand for WithRedaction(WithPIIDetectors(detectors...)).
+func WithPIIMasking(detectors ...PIIDetector) Opt {
+	if len(detectors) == 0 {
+		detectors = DefaultPIIDetectors()
+	}
+
+	return WithRedaction(WithPIIDetectors(detectors...))
+}
+
+// mask replaces the valid matches of the detector in s with the result of masker
+func (d PIIDetector) mask(s string, masker Masker) string {
+	return d.Pattern.ReplaceAllStringFunc(s, func(match string) string {
+		if d.Valid != nil && !d.Valid(match) {
+			return match
+		}
+		return masker(match)
+	})
+}
+
+// validLuhn reports whether the digits of s pass the Luhn check
+func validLuhn(s string) bool {
+	sum, count := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if count%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		count++
+	}
+
+	return count >= 13 && count <= 19 && sum%10 == 0
+}
+
+// validIBAN reports whether s, ignoring spaces, has valid IBAN check digits
+func validIBAN(s string) bool {
+	s = strings.ReplaceAll(s, " ", "")
+	if len(s) < 15 || len(s) > 34 {
+		return false
+	}
+
+	remainder := 0
+	for _, c := range s[4:] + s[:4] {
+		switch {
+		case c >= '0' && c <= '9':
+			remainder = (remainder*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			remainder = (remainder*100 + int(c-'A'+10)) % 97
+		default:
+			return false
+		}
+	}
+
+	return remainder == 1
+}
+
+// validIP reports whether s is an IP address
+func validIP(s string) bool {
+	return net.ParseIP(s) != nil
+}
@@ -0,0 +1,65 @@
+package zerolog
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithPIIMasking(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithTimestamp(), WithPIIMasking())
+
+	l.Infof("user %s paid with %s", "alice@example.com", "4111 1111 1111 1111")
+	assert.NotContains(t, b.String(), "alice@example.com")
+	assert.NotContains(t, b.String(), "4111 1111 1111 1111")
+	assert.Contains(t, b.String(), `"message":"user *** paid with ***"`)
+	assert.Contains(t, b.String(), `"time":"`)
+}
+
+func TestWithPIIMaskingFields(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithPIIMasking(EmailDetector))
+
+	l.With("contact", map[string]interface{}{"emails": []interface{}{"a@b.io", "none"}}, "count", 2).Info("foo")
+
+	assert.Equal(t, `{"level":"info","contact":{"emails":["***","none"]},"count":2,"message":"foo"}
+`, b.String())
+}
+
+func TestPIIDetectors(t *testing.T) {
+	tests := []struct {
+		detector PIIDetector
+		in       string
+		out      string
+	}{
+		{EmailDetector, "mail john.doe+news@mail.example.co.uk now", "mail *** now"},
+		{EmailDetector, "not an @ address", "not an @ address"},
+		{CardNumberDetector, "card 4111-1111-1111-1111 ok", "card *** ok"},
+		{CardNumberDetector, "card 4111111111111112 bad", "card 4111111111111112 bad"},
+		{CardNumberDetector, "order 123456", "order 123456"},
+		{IBANDetector, "iban DE89 3704 0044 0532 0130 00.", "iban ***."},
+		{IBANDetector, "iban GB82WEST12345698765432", "iban ***"},
+		{IBANDetector, "iban DE00370400440532013000", "iban DE00370400440532013000"},
+		{PhoneDetector, "call +44 20 7946 0958", "call ***"},
+		{PhoneDetector, "call (555) 123-4567 or 555.123.4567", "call *** or ***"},
+		{PhoneDetector, "on 2022-11-11 at 10:00", "on 2022-11-11 at 10:00"},
+		{IPv4Detector, "from 192.168.1.10:8080", "from ***:8080"},
+		{IPv4Detector, "version 999.1.2.3", "version 999.1.2.3"},
+		{IPv6Detector, "from 2001:db8::1 and ::1", "from *** and ***"},
+		{IPv6Detector, "at 10:00:00", "at 10:00:00"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.detector.Name, func(t *testing.T) {
+			assert.Equal(t, test.out, test.detector.mask(test.in, MaskFixed(DefaultMask)))
+		})
+	}
+}
+
+func TestWithPIIDetectorsMasker(t *testing.T) {
+	r := NewRedactor(WithPIIDetectors(CardNumberDetector), WithRedactMasker(MaskPartial(0, 4)))
+
+	assert.Equal(t, `{"message":"card ************1111"}`, string(r.Redact([]byte(`{"message":"card 4111111111111111"}`))))
+}
@@ -0,0 +1,158 @@
+package zerolog
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/rs/zerolog"
+)
+
+const maxStackDepth = 64
+
+type (
+	RecoveryOptions struct {
+		logger      *Logger
+		message     string
+		contentType string
+		body        []byte
+		handler     func(ctx context.Context, c *app.RequestContext, err interface{})
+	}
+
+	RecoveryOpt func(opts *RecoveryOptions)
+)
+
+func newRecoveryOptions(options []RecoveryOpt) *RecoveryOptions {
+	opts := &RecoveryOptions{
+		message: "panic recovered",
+	}
+
+	for _, set := range options {
+		set(opts)
+	}
+
+	return opts
+}
+
+// WithRecoveryLogger allows to specify the logger panics are logged with when the request context has none.
+// By default, the logger returned by GetLogger at request time is used.
+func WithRecoveryLogger(logger *Logger) RecoveryOpt {
+	return func(opts *RecoveryOptions) {
+		opts.logger = logger
+	}
+}
+
+// WithRecoveryMessage allows to specify the message of panic logs. By default, it is set to "panic recovered".
+func WithRecoveryMessage(message string) RecoveryOpt {
+	return func(opts *RecoveryOptions) {
+		opts.message = message
+	}
+}
+
+// WithRecoveryBody allows to specify the body of the 500 response sent after a panic. By default, the body is empty.
+func WithRecoveryBody(contentType string, body []byte) RecoveryOpt {
+	return func(opts *RecoveryOptions) {
+		opts.contentType = contentType
+		opts.body = body
+	}
+}
+
+// WithRecoveryHandler allows to specify the function writing the response after a panic has been logged,
+// replacing the 500 response
+func WithRecoveryHandler(handler func(ctx context.Context, c *app.RequestContext, err interface{})) RecoveryOpt {
+	return func(opts *RecoveryOptions) {
+		opts.handler = handler
+	}
+}
+
+// Recovery returns a middleware that recovers from panics in the following handlers, logs them at error level
+// with the panic value, stack trace, route and request id, and responds with a 500 status
+func Recovery(options ...RecoveryOpt) app.HandlerFunc {
+	opts := newRecoveryOptions(options)
+
+	return func(ctx context.Context, c *app.RequestContext) {
+		defer func() {
+			err := recover()
+			if err == nil {
+				return
+			}
+
+			opts.log(ctx, c, err, stack())
+
+			if opts.handler != nil {
+				opts.handler(ctx, c, err)
+				return
+			}
+
+			c.Response.ResetBody()
+			c.AbortWithStatus(consts.StatusInternalServerError)
+			if opts.body != nil {
+				c.Data(consts.StatusInternalServerError, opts.contentType, opts.body)
+			}
+		}()
+
+		c.Next(ctx)
+	}
+}
+
+func (opts *RecoveryOptions) log(ctx context.Context, c *app.RequestContext, err interface{}, stack []string) {
+	base := opts.logger
+	if base == nil {
+		base = GetLogger()
+	}
+	if base == nil {
+		base = From(*zerolog.Ctx(ctx))
+	}
+
+	e := base.traceFields(newEvent(base.ctxLogger(ctx).current(), hlog.LevelError), ctx)
+	if e == nil {
+		return
+	}
+
+	if cause, ok := err.(error); ok {
+		e = e.Err(cause)
+	}
+	e = e.Str("panic", fmt.Sprint(err)).
+		Strs("stack", stack).
+		Str("method", string(c.Method())).
+		Str("path", string(c.Path())).
+		Str("route", c.FullPath())
+
+	// the request id is a field of the context logger when the RequestID middleware runs first
+	if _, ok := RequestIDFromContext(ctx); !ok {
+		if id := GetRequestID(c); id != "" {
+			e = e.Str(requestIDField(c), id)
+		}
+	}
+
+	e.Msg(opts.message)
+}
+
+// stack returns the frames of the panicking goroutine, from the panic site down,
+// formatted as "function file:line" without the runtime and recovery frames
+func stack() []string {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var stack []string
+	panicking := false
+	for {
+		frame, more := frames.Next()
+		switch {
+		case frame.Function == "runtime.gopanic":
+			panicking = true
+		case panicking && !strings.HasPrefix(frame.Function, "runtime."):
+			stack = append(stack, frame.Function+" "+frame.File+":"+strconv.Itoa(frame.Line))
+		}
+
+		if !more {
+			return stack
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package zerolog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/json"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/cloudwego/hertz/pkg/route"
+	"github.com/stretchr/testify/assert"
+)
+
+type PanicLog struct {
+	Level     string   `json:"level"`
+	Error     string   `json:"error"`
+	Panic     string   `json:"panic"`
+	Stack     []string `json:"stack"`
+	Method    string   `json:"method"`
+	Path      string   `json:"path"`
+	Route     string   `json:"route"`
+	RequestID string   `json:"request_id"`
+	Message   string   `json:"message"`
+}
+
+func newPanicEngine(handlers ...app.HandlerFunc) *route.Engine {
+	engine := newTestEngine(handlers...)
+	engine.GET("/panic/:id", func(ctx context.Context, c *app.RequestContext) {
+		c.String(consts.StatusOK, "partial")
+		panic("boom")
+	})
+	engine.GET("/error", func(ctx context.Context, c *app.RequestContext) {
+		panic(errors.New("failed"))
+	})
+	return engine
+}
+
+func TestRecovery(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b))
+	engine := newPanicEngine(
+		Recovery(WithRecoveryLogger(l)),
+		RequestID(WithRequestIDGenerator(func() string { return "abc" })),
+	)
+
+	w := ut.PerformRequest(engine, "GET", "/panic/42", nil)
+
+	assert.Equal(t, consts.StatusInternalServerError, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, "abc", w.Header().Get(RequestIDHeader))
+
+	log := &PanicLog{}
+	assert.NoError(t, json.Unmarshal(b.Bytes(), log))
+	assert.Equal(t, "error", log.Level)
+	assert.Equal(t, "boom", log.Panic)
+	assert.Empty(t, log.Error)
+	assert.Equal(t, "GET", log.Method)
+	assert.Equal(t, "/panic/42", log.Path)
+	assert.Equal(t, "/panic/:id", log.Route)
+	assert.Equal(t, "abc", log.RequestID)
+	assert.Equal(t, "panic recovered", log.Message)
+
+	assert.NotEmpty(t, log.Stack)
+	assert.True(t, strings.HasPrefix(log.Stack[0], "github.com/sillen102/hertz-contrib-zerolog.newPanicEngine.func1 "), log.Stack[0])
+	assert.Contains(t, log.Stack[0], "recovery_test.go:")
+	for _, frame := range log.Stack {
+		assert.False(t, strings.HasPrefix(frame, "runtime."), frame)
+	}
+}
+
+func TestRecoveryRequestIDField(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b))
+	engine := newPanicEngine(
+		Recovery(WithRecoveryLogger(l)),
+		RequestID(WithRequestIDField("rid"), WithRequestIDGenerator(func() string { return "abc" })),
+	)
+
+	ut.PerformRequest(engine, "GET", "/panic/42", nil)
+
+	assert.Contains(t, b.String(), `"rid":"abc"`)
+	assert.NotContains(t, b.String(), `"request_id"`)
+}
+
+func TestRecoveryContextLogger(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b))
+	engine := newPanicEngine(
+		RequestID(WithRequestIDLogger(l), WithRequestIDGenerator(func() string { return "abc" })),
+		Recovery(WithRecoveryMessage("handler panicked")),
+	)
+
+	ut.PerformRequest(engine, "GET", "/error", nil)
+
+	assert.Equal(t, 1, strings.Count(b.String(), `"request_id":"abc"`))
+
+	log := &PanicLog{}
+	assert.NoError(t, json.Unmarshal(b.Bytes(), log))
+	assert.Equal(t, "failed", log.Error)
+	assert.Equal(t, "failed", log.Panic)
+	assert.Equal(t, "handler panicked", log.Message)
+}
+
+func TestRecoveryBody(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}))
+	engine := newPanicEngine(Recovery(WithRecoveryLogger(l), WithRecoveryBody("application/json", []byte(`{"error":"internal"}`))))
+
+	w := ut.PerformRequest(engine, "GET", "/panic/1", nil)
+
+	assert.Equal(t, consts.StatusInternalServerError, w.Code)
+	assert.Equal(t, `{"error":"internal"}`, w.Body.String())
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	w = ut.PerformRequest(engine, "GET", "/users/1", nil)
+	assert.Equal(t, consts.StatusOK, w.Code)
+}
+
+func TestRecoveryHandler(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}))
+	var recovered interface{}
+	engine := newPanicEngine(Recovery(WithRecoveryLogger(l), WithRecoveryHandler(func(ctx context.Context, c *app.RequestContext, err interface{}) {
+		recovered = err
+		c.AbortWithStatus(consts.StatusServiceUnavailable)
+	})))
+
+	w := ut.PerformRequest(engine, "GET", "/panic/1", nil)
+
+	assert.Equal(t, consts.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "boom", recovered)
+}
@@ -0,0 +1,396 @@
+package zerolog
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultMask is the replacement of redacted values when no masker is specified
+const DefaultMask = "***"
+
+// DefaultRedactKeys are the field names always redacted by a Redactor
+var DefaultRedactKeys = []string{
+	"password",
+	"passwd",
+	"secret",
+	"token",
+	"access_token",
+	"refresh_token",
+	"api_key",
+	"apikey",
+	"x-api-key",
+	"authorization",
+	"proxy-authorization",
+	"cookie",
+	"set-cookie",
+}
+
+var _ zerolog.LevelWriter = (*redactWriter)(nil)
+
+type (
+	RedactOptions struct {
+		keys      map[string]struct{}
+		paths     [][]string
+		patterns  []*regexp.Regexp
+		detectors []PIIDetector
+		masker    Masker
+	}
+
+	RedactOpt func(opts *RedactOptions)
+
+	// Masker returns the replacement of a redacted value.
+	// Values other than strings are passed as their JSON encoding.
+	Masker func(value string) string
+
+	// redactWriter redacts events before writing them to the wrapped writer
+	redactWriter struct {
+		redactor *Redactor
+		out      io.Writer
+	}
+)
+
+// Redactor masks sensitive values of encoded events: fields by name at any depth, fields by path,
+// matches of regular expressions in the message and personal data found by detectors
+type Redactor struct {
+	opts *RedactOptions
+}
+
+func newRedactOptions(options []RedactOpt) *RedactOptions {
+	opts := &RedactOptions{
+		keys:   make(map[string]struct{}, len(DefaultRedactKeys)),
+		masker: MaskFixed(DefaultMask),
+	}
+	for _, key := range DefaultRedactKeys {
+		opts.keys[key] = struct{}{}
+	}
+
+	for _, set := range options {
+		set(opts)
+	}
+
+	return opts
+}
+
+// WithRedactKeys redacts the fields with one of the names, compared case-insensitively, at any depth.
+// The keys are added to DefaultRedactKeys.
+func WithRedactKeys(keys ...string) RedactOpt {
+	return func(opts *RedactOptions) {
+		for _, key := range keys {
+			opts.keys[strings.ToLower(key)] = struct{}{}
+		}
+	}
+}
+
+// WithRedactPaths redacts the fields matching one of the dot separated glob patterns, e.g. "user.*.secret".
+// Each segment is matched with path.Match and "**" matches any number of segments.
+// The elements of an array share the path of the array.
+func WithRedactPaths(patterns ...string) RedactOpt {
+	return func(opts *RedactOptions) {
+		for _, pattern := range patterns {
+			opts.paths = append(opts.paths, strings.Split(pattern, "."))
+		}
+	}
+}
+
+// WithRedactPatterns masks the matches of the regular expressions in the message
+func WithRedactPatterns(patterns ...*regexp.Regexp) RedactOpt {
+	return func(opts *RedactOptions) {
+		opts.patterns = append(opts.patterns, patterns...)
+	}
+}
+
+// WithRedactMasker allows to specify how redacted values are replaced. By default, it is set to MaskFixed(DefaultMask).
+func WithRedactMasker(masker Masker) RedactOpt {
+	return func(opts *RedactOptions) {
+		opts.masker = masker
+	}
+}
+
+// MaskFixed replaces values with mask
+func MaskFixed(mask string) Masker {
+	return func(string) string {
+		return mask
+	}
+}
+
+// MaskHash replaces values with a truncated SHA-256 of the salt followed by the value, e.g. "sha256:9f86d081884c7d65",
+// so that equal values can be correlated without being revealed
+func MaskHash(salt string) Masker {
+	return func(value string) string {
+		sum := sha256.Sum256([]byte(salt + value))
+		return "sha256:" + hex.EncodeToString(sum[:8])
+	}
+}
+
+// MaskPartial keeps the first prefix and last suffix characters of values and replaces the others with '*'.
+// Values too short to keep anything are masked entirely.
+func MaskPartial(prefix, suffix int) Masker {
+	return func(value string) string {
+		runes := []rune(value)
+		if prefix < 0 || suffix < 0 || prefix+suffix >= len(runes) {
+			return strings.Repeat("*", len(runes))
+		}
+
+		masked := strings.Repeat("*", len(runes)-prefix-suffix)
+		return string(runes[:prefix]) + masked + string(runes[len(runes)-suffix:])
+	}
+}
+
+// WithRedaction redacts the events of the logger before they are written to its output.
+// With From, the output must be given with WithOutput, since events are otherwise written to os.Stdout.
+func WithRedaction(options ...RedactOpt) Opt {
+	r := NewRedactor(options...)
+	return func(opts *Options) {
+		opts.stages = append(opts.stages, unnamedStage(r.Writer))
+	}
+}
+
+// NewRedactor returns a Redactor that redacts DefaultRedactKeys and what the options specify
+func NewRedactor(options ...RedactOpt) *Redactor {
+	return &Redactor{opts: newRedactOptions(options)}
+}
+
+// Writer returns a writer that redacts events before writing them to out
+func (r *Redactor) Writer(out io.Writer) io.Writer {
+	return &redactWriter{redactor: r, out: out}
+}
+
+// Redact returns the event with sensitive values masked.
+// Input that is not a JSON object is returned as is.
+func (r *Redactor) Redact(event []byte) []byte {
+	raw := bytes.TrimRight(event, "\n")
+	if len(raw) == 0 || raw[0] != '{' {
+		return event
+	}
+
+	redacted, err := r.redactObject(make([]byte, 0, len(event)), raw, nil, true)
+	if err != nil {
+		return event
+	}
+
+	return append(redacted, event[len(raw):]...)
+}
+
+// Match reports whether a field with the dot separated path is redacted
+func (r *Redactor) Match(key string) bool {
+	return r.match(strings.Split(key, "."))
+}
+
+// Mask returns the replacement of a redacted value
+func (r *Redactor) Mask(value string) string {
+	return r.opts.masker(value)
+}
+
+func (r *Redactor) match(segments []string) bool {
+	if _, ok := r.opts.keys[strings.ToLower(segments[len(segments)-1])]; ok {
+		return true
+	}
+
+	for _, pattern := range r.opts.paths {
+		if matchPath(pattern, segments) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *Redactor) redactValue(buf, raw []byte, segments []string) ([]byte, error) {
+	switch raw[0] {
+	case '{':
+		return r.redactObject(buf, raw, segments, false)
+	case '[':
+		return r.redactArray(buf, raw, segments)
+	case '"':
+		return r.redactString(buf, raw, r.detectPII), nil
+	default:
+		return append(buf, raw...), nil
+	}
+}
+
+func (r *Redactor) redactObject(buf, raw []byte, segments []string, top bool) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+
+	buf = append(buf, '{')
+	for first := true; dec.More(); first = false {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := token.(string)
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		if !first {
+			buf = append(buf, ',')
+		}
+		buf = appendJSONString(buf, key)
+		buf = append(buf, ':')
+
+		fieldPath := append(segments[:len(segments):len(segments)], strings.Split(key, ".")...)
+		switch {
+		case r.match(fieldPath):
+			buf = appendJSONString(buf, r.opts.masker(rawString(value)))
+		case top && key == zerolog.MessageFieldName && value[0] == '"':
+			buf = r.redactString(buf, value, r.redactMessage)
+		case top && (key == zerolog.LevelFieldName || key == zerolog.TimestampFieldName || key == zerolog.CallerFieldName):
+			buf = append(buf, value...)
+		default:
+			if buf, err = r.redactValue(buf, value, fieldPath); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return append(buf, '}'), nil
+}
+
+func (r *Redactor) redactArray(buf, raw []byte, segments []string) ([]byte, error) {
+	var values []json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, err
+	}
+
+	buf = append(buf, '[')
+	for i, value := range values {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+
+		var err error
+		if buf, err = r.redactValue(buf, value, segments); err != nil {
+			return nil, err
+		}
+	}
+
+	return append(buf, ']'), nil
+}
+
+// redactString appends the JSON string raw with fn applied, keeping raw as is when nothing changes
+func (r *Redactor) redactString(buf, raw []byte, fn func(s string) string) []byte {
+	if len(r.opts.patterns) == 0 && len(r.opts.detectors) == 0 {
+		return append(buf, raw...)
+	}
+
+	s := rawString(raw)
+	if redacted := fn(s); redacted != s {
+		return appendJSONString(buf, redacted)
+	}
+
+	return append(buf, raw...)
+}
+
+func (r *Redactor) redactMessage(msg string) string {
+	for _, pattern := range r.opts.patterns {
+		msg = pattern.ReplaceAllStringFunc(msg, r.opts.masker)
+	}
+
+	return r.detectPII(msg)
+}
+
+func (r *Redactor) detectPII(s string) string {
+	for _, detector := range r.opts.detectors {
+		s = detector.mask(s, r.opts.masker)
+	}
+
+	return s
+}
+
+func (w *redactWriter) Write(p []byte) (int, error) {
+	if _, err := w.out.Write(w.redactor.Redact(p)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (w *redactWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	lw, ok := w.out.(zerolog.LevelWriter)
+	if !ok {
+		return w.Write(p)
+	}
+
+	if _, err := lw.WriteLevel(level, w.redactor.Redact(p)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// matchPath matches the segments of a field path against the segments of a glob pattern
+func matchPath(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchPath(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+
+	return matchPath(pattern[1:], segments[1:])
+}
+
+// rawString returns a JSON string unquoted and any other JSON value as is
+func rawString(raw json.RawMessage) string {
+	var s string
+	if raw[0] == '"' && json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+
+	return string(raw)
+}
+
+// appendJSONString appends s to buf as a JSON string, without escaping HTML characters like zerolog
+func appendJSONString(buf []byte, s string) []byte {
+	if utf8.ValidString(s) && !strings.ContainsAny(s, "\"\\") && printable(s) {
+		buf = append(buf, '"')
+		buf = append(buf, s...)
+		return append(buf, '"')
+	}
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+
+	return append(buf, bytes.TrimRight(b.Bytes(), "\n")...)
+}
+
+// printable reports whether s has no control characters that need escaping in JSON
+func printable(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 {
+			return false
+		}
+	}
+
+	return true
+}
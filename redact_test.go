@@ -0,0 +1,114 @@
+package zerolog
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRedaction(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithRedaction())
+
+	l.With("user", "alice", "Password", "hunter2").Infow("login", "Authorization", "Bearer abc")
+
+	assert.Equal(t, `{"level":"info","user":"alice","Password":"***","Authorization":"***","message":"login"}
+`, b.String())
+}
+
+func TestWithRedactionNested(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithRedaction(WithRedactKeys("ssn")))
+
+	l.WithField("user", map[string]interface{}{
+		"name":    "alice",
+		"ssn":     "123-45-6789",
+		"tokens":  []interface{}{map[string]interface{}{"token": "abc", "scope": "read"}},
+		"profile": map[string]interface{}{"age": 42},
+	}).Info("foo")
+
+	assert.Equal(t, `{"level":"info","user":{"name":"alice","profile":{"age":42},"ssn":"***","tokens":[{"scope":"read","token":"***"}]},"message":"foo"}
+`, b.String())
+}
+
+func TestWithRedactionPaths(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithRedaction(WithRedactPaths("card.number", "**.iban", "headers.x-*")))
+
+	l.WithGroup("payment").With(
+		"card", map[string]interface{}{"number": "4111111111111111", "brand": "visa"},
+		"account", map[string]interface{}{"bank": map[string]interface{}{"iban": "DE89370400440532013000"}},
+		"headers", map[string]interface{}{"x-signature": "abc", "accept": "*/*"},
+	).Info("foo")
+	l.With("card", map[string]interface{}{"number": 4111111111111111}).Info("bar")
+
+	assert.Equal(t, `{"level":"info","payment.card":{"brand":"visa","number":"4111111111111111"},"payment.account":{"bank":{"iban":"***"}},"payment.headers":{"accept":"*/*","x-signature":"abc"},"message":"foo"}
+{"level":"info","card":{"number":"***"},"message":"bar"}
+`, b.String())
+}
+
+func TestWithRedactionPatterns(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithRedaction(
+		WithRedactPatterns(regexp.MustCompile(`key=\w+`)),
+		WithRedactMasker(MaskPartial(4, 0)),
+	))
+
+	l.Infow("call with key=s3cr3t failed", "detail", "key=s3cr3t")
+
+	assert.Equal(t, `{"level":"info","detail":"key=s3cr3t","message":"call with key=****** failed"}
+`, b.String())
+}
+
+func TestWithRedactionSetOutput(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithRedaction())
+	l.SetOutput(b)
+
+	l.Infow("foo", "token", "abc")
+
+	assert.Equal(t, `{"level":"info","token":"***","message":"foo"}
+`, b.String())
+}
+
+func TestWithRedactionFrom(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := From(zerolog.New(nil).With().Str("secret", "abc").Logger(), WithOutput(b), WithRedaction())
+
+	l.Info("foo")
+
+	assert.Equal(t, `{"level":"info","secret":"***","message":"foo"}
+`, b.String())
+}
+
+func TestMaskers(t *testing.T) {
+	assert.Equal(t, "[redacted]", MaskFixed("[redacted]")("foo"))
+	assert.Equal(t, "sha256:2c26b46b68ffc68f", MaskHash("")("foo"))
+	assert.NotEqual(t, MaskHash("")("foo"), MaskHash("salt")("foo"))
+	assert.Equal(t, "41**********1111", MaskPartial(2, 4)("4111111111111111"))
+	assert.Equal(t, "*****", MaskPartial(2, 4)("12345"))
+	assert.Equal(t, "é**ü", MaskPartial(1, 1)("éaaü"))
+}
+
+func TestRedactor(t *testing.T) {
+	r := NewRedactor(WithRedactMasker(MaskHash("")))
+
+	assert.Equal(t, "not json\n", string(r.Redact([]byte("not json\n"))))
+	assert.Equal(t, `{"broken":`, string(r.Redact([]byte(`{"broken":`))))
+	assert.Equal(t, `{"token":"sha256:2c26b46b68ffc68f","html":"<a href=\"x\">","n":1}`+"\n",
+		string(r.Redact([]byte(`{"token":"foo","html":"<a href=\"x\">","n":1}`+"\n"))))
+	assert.True(t, r.Match("auth.Cookie"))
+	assert.False(t, r.Match("user"))
+}
+
+func TestRedactionLevelWriter(t *testing.T) {
+	rec := &levelRecorder{}
+	l := New(WithOutput(rec), WithRedaction())
+
+	l.Error("foo")
+
+	assert.Equal(t, []zerolog.Level{zerolog.ErrorLevel}, rec.levels)
+}
@@ -0,0 +1,295 @@
+package zerolog
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/rs/zerolog"
+)
+
+// LoggerFieldName is the field stamped with the name of a named logger
+const LoggerFieldName = "logger"
+
+// Registry hands out named loggers derived from a root logger. Named loggers share the output and hooks
+// of the root and stamp their name in the LoggerFieldName field. They stay linked to the root, following
+// its later changes of output and level. Their level can be overridden per name, where an override for
+// "billing" also applies to "billing.stripe" unless that has an override of its own.
+type Registry struct {
+	mu       sync.Mutex
+	root     *Logger
+	fallback atomic.Pointer[Logger]
+	loggers  map[string]*Logger
+	levels   map[string]hlog.Level
+}
+
+type (
+	// namedLogger links a named logger to the root of its registry, resolved each time the logger is used
+	namedLogger struct {
+		registry *Registry
+		name     string
+
+		mu       sync.Mutex
+		level    *hlog.Level
+		out      io.Writer
+		snapshot atomic.Pointer[namedSnapshot]
+	}
+
+	// namedSnapshot is the named logger derived from a state of the root logger
+	namedSnapshot struct {
+		root   *zerolog.Logger
+		logger *Logger
+	}
+)
+
+var defaultRegistry = NewRegistry(nil)
+
+// NewRegistry returns a registry of loggers named after root.
+// If root is nil, named loggers follow the logger returned by GetLogger, so they can be created
+// before hlog.SetLogger is called.
+func NewRegistry(root *Logger) *Registry {
+	return &Registry{
+		root:    root,
+		loggers: map[string]*Logger{},
+		levels:  map[string]hlog.Level{},
+	}
+}
+
+// DefaultRegistry returns the registry used by Named and SetLevels
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Named returns the logger with the given name from the default registry, creating it on first use
+func Named(name string) *Logger {
+	return defaultRegistry.Named(name)
+}
+
+// SetLevels replaces the level overrides of the default registry, see Registry.SetLevels
+func SetLevels(spec string) error {
+	return defaultRegistry.SetLevels(spec)
+}
+
+// Named returns the logger with the given name, creating it on first use
+func (r *Registry) Named(name string) *Logger {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if logger, ok := r.loggers[name]; ok {
+		return logger
+	}
+
+	logger := &Logger{named: &namedLogger{registry: r, name: name}}
+	if level, ok := r.resolve(name); ok {
+		logger.named.setLevel(level)
+	}
+	r.loggers[name] = logger
+
+	return logger
+}
+
+// Lookup returns the named logger if it has been created
+func (r *Registry) Lookup(name string) (*Logger, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	logger, ok := r.loggers[name]
+	return logger, ok
+}
+
+// Names returns the sorted names of the loggers created so far
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.loggers))
+	for name := range r.loggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// SetLevels replaces the level overrides with the comma separated name=level pairs of spec,
+// e.g. "billing=debug,billing.stripe=info,*=warn". The name * sets the level of the root logger and of
+// every named logger without a more specific override. If spec is invalid, no level is changed.
+func (r *Registry) SetLevels(spec string) error {
+	levels, err := ParseLevels(spec)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.levels = levels
+	r.apply()
+
+	return nil
+}
+
+// SetLevel overrides the level of the named logger and of its descendants without a more specific override
+func (r *Registry) SetLevel(name string, level hlog.Level) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.levels[name] = level
+	r.apply()
+}
+
+// ParseLevels parses comma separated name=level pairs such as "billing=debug,*=warn"
+func ParseLevels(spec string) (map[string]hlog.Level, error) {
+	levels := map[string]hlog.Level{}
+
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, levelName, found := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid level override %q, expected name=level", pair)
+		}
+
+		level, err := ParseLevel(levelName)
+		if err != nil {
+			return nil, err
+		}
+		levels[name] = level
+	}
+
+	return levels, nil
+}
+
+// apply sets the level of the root and of every named logger from the overrides.
+// Named loggers without override follow the level of the root. Callers must hold r.mu.
+func (r *Registry) apply() {
+	if level, ok := r.levels["*"]; ok {
+		r.rootLogger().SetLevel(level)
+	}
+
+	for name, logger := range r.loggers {
+		if level, ok := r.resolve(name); ok {
+			logger.named.setLevel(level)
+		} else {
+			logger.named.clearLevel()
+		}
+	}
+}
+
+// resolve returns the override for name, walking up the dot separated hierarchy. Callers must hold r.mu.
+func (r *Registry) resolve(name string) (hlog.Level, bool) {
+	for {
+		if level, ok := r.levels[name]; ok {
+			return level, true
+		}
+
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+
+	level, ok := r.levels["*"]
+	return level, ok
+}
+
+// rootLogger returns the root logger: the one given to NewRegistry, else the one returned by GetLogger.
+// Until a logger is set with hlog.SetLogger, a logger created with New stands in without becoming the root.
+func (r *Registry) rootLogger() *Logger {
+	if r.root != nil {
+		return r.root
+	}
+	if root := GetLogger(); root != nil {
+		return root
+	}
+
+	if fallback := r.fallback.Load(); fallback != nil {
+		return fallback
+	}
+	r.fallback.CompareAndSwap(nil, New())
+
+	return r.fallback.Load()
+}
+
+// logger returns the named logger derived from the current state of the root logger
+func (n *namedLogger) logger() *Logger {
+	root := n.registry.rootLogger().resolve()
+	if snapshot := n.snapshot.Load(); snapshot != nil && snapshot.root == root.current() {
+		return snapshot.logger
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	root.mu.Lock()
+	log, out := root.current(), root.out
+	root.mu.Unlock()
+
+	if snapshot := n.snapshot.Load(); snapshot != nil && snapshot.root == log {
+		return snapshot.logger
+	}
+
+	logger := root.derive(*log).withFields([]interface{}{LoggerFieldName, n.name})
+	logger.name = n.name
+	if n.out != nil {
+		out = n.out
+	}
+	logger.out = out
+
+	// the writer stages are applied again so that they see the events as written by the named logger
+	named := *logger.log.Load()
+	if out != nil && (n.out != nil || len(root.stages) > 0) {
+		named = named.Output(wrapOutput(out, root.stages, n.name))
+	}
+	if n.level != nil {
+		named = named.Level(matchHlogLevel(*n.level))
+	}
+	logger.log.Store(&named)
+
+	n.snapshot.Store(&namedSnapshot{root: log, logger: logger})
+	return logger
+}
+
+// setLevel overrides the level of the root for the named logger
+func (n *namedLogger) setLevel(level hlog.Level) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.level = &level
+	n.snapshot.Store(nil)
+}
+
+// hasLevel reports whether the level of the named logger is overridden rather than followed from the root
+func (n *namedLogger) hasLevel() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.level != nil
+}
+
+// clearLevel makes the named logger follow the level of the root again
+func (n *namedLogger) clearLevel() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.level = nil
+	n.snapshot.Store(nil)
+}
+
+// setOutput overrides the output of the root for the named logger
+func (n *namedLogger) setOutput(out io.Writer) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.out = out
+	n.snapshot.Store(nil)
+}
@@ -0,0 +1,151 @@
+package zerolog
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/cloudwego/hertz/pkg/common/json"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryNamed(t *testing.T) {
+	b := &bytes.Buffer{}
+	r := NewRegistry(New(WithOutput(b), WithLevel(hlog.LevelInfo), WithField("service", "shop")))
+
+	billing := r.Named("billing")
+	assert.Same(t, billing, r.Named("billing"))
+
+	billing.Info("charged")
+	assert.Equal(
+		t,
+		`{"level":"info","service":"shop","logger":"billing","message":"charged"}
+`,
+		b.String(),
+	)
+
+	b.Reset()
+	billing.Debug("details")
+	assert.Empty(t, b.String())
+
+	lookedUp, ok := r.Lookup("billing")
+	assert.True(t, ok)
+	assert.Same(t, billing, lookedUp)
+
+	_, ok = r.Lookup("shipping")
+	assert.False(t, ok)
+}
+
+func TestRegistrySetLevels(t *testing.T) {
+	root := New(WithLevel(hlog.LevelInfo))
+	r := NewRegistry(root)
+	billing := r.Named("billing")
+	stripe := r.Named("billing.stripe")
+	shipping := r.Named("shipping")
+
+	err := r.SetLevels("billing=debug, billing.stripe.webhooks=trace, *=error")
+
+	assert.NoError(t, err)
+	assert.Equal(t, hlog.LevelDebug, billing.GetLevel())
+	assert.Equal(t, hlog.LevelDebug, stripe.GetLevel())
+	assert.Equal(t, hlog.LevelError, shipping.GetLevel())
+	assert.Equal(t, hlog.LevelError, root.GetLevel())
+	assert.Equal(t, hlog.LevelTrace, r.Named("billing.stripe.webhooks").GetLevel())
+
+	r.SetLevel("billing.stripe", hlog.LevelWarn)
+	assert.Equal(t, hlog.LevelWarn, stripe.GetLevel())
+	assert.Equal(t, hlog.LevelDebug, billing.GetLevel())
+
+	err = r.SetLevels("billing=verbose")
+
+	assert.Error(t, err)
+	assert.Equal(t, hlog.LevelWarn, stripe.GetLevel())
+
+	err = r.SetLevels("")
+
+	assert.NoError(t, err)
+	assert.Equal(t, hlog.LevelError, billing.GetLevel())
+	assert.Equal(t, hlog.LevelError, stripe.GetLevel())
+}
+
+func TestRegistryFollowsRoot(t *testing.T) {
+	root := New(WithOutput(&bytes.Buffer{}), WithLevel(hlog.LevelInfo))
+	r := NewRegistry(root)
+	billing := r.Named("billing")
+	shipping := r.Named("shipping")
+	shipping.SetLevel(hlog.LevelWarn)
+
+	b := &bytes.Buffer{}
+	root.SetOutput(b)
+	root.SetLevel(hlog.LevelDebug)
+
+	billing.Debug("details")
+	shipping.Info("dropped by override")
+	assert.Equal(t, `{"level":"debug","logger":"billing","message":"details"}
+`, b.String())
+	assert.Equal(t, hlog.LevelDebug, billing.GetLevel())
+	assert.Equal(t, hlog.LevelWarn, shipping.GetLevel())
+
+	own := &bytes.Buffer{}
+	billing.SetOutput(own)
+	billing.Info("charged")
+	assert.Equal(t, `{"level":"info","logger":"billing","message":"charged"}
+`, own.String())
+}
+
+func TestRegistryLazyRoot(t *testing.T) {
+	r := NewRegistry(nil)
+	billing := r.Named("billing")
+
+	b := &bytes.Buffer{}
+	hlog.SetLogger(New(WithOutput(b)))
+	defer hlog.SetLogger(New())
+
+	billing.Info("charged")
+	assert.Equal(t, `{"level":"info","logger":"billing","message":"charged"}
+`, b.String())
+}
+
+func TestRegistryNames(t *testing.T) {
+	r := NewRegistry(New())
+	r.Named("shipping")
+	r.Named("billing")
+
+	assert.Equal(t, []string{"billing", "shipping"}, r.Names())
+}
+
+func TestParseLevels(t *testing.T) {
+	levels, err := ParseLevels("billing=debug,*=warn,")
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]hlog.Level{"billing": hlog.LevelDebug, "*": hlog.LevelWarn}, levels)
+
+	_, err = ParseLevels("billing")
+	assert.Error(t, err)
+
+	_, err = ParseLevels("=debug")
+	assert.Error(t, err)
+}
+
+func TestLevelHandlerRegistry(t *testing.T) {
+	r := NewRegistry(New(WithLevel(hlog.LevelInfo)))
+	billing := r.Named("billing")
+	engine := newLevelEngine(WithLevelHandlerLogger(New(WithLevel(hlog.LevelWarn))), WithLevelHandlerRegistry(r))
+
+	ut.PerformRequest(engine, "PUT", "/admin/log/level?logger=billing&level=debug", nil)
+	assert.Equal(t, hlog.LevelDebug, billing.GetLevel())
+
+	w := ut.PerformRequest(engine, "GET", "/admin/log/level", nil)
+
+	resp := &struct {
+		Loggers []LevelResponse `json:"loggers"`
+	}{}
+	err := json.Unmarshal(w.Body.Bytes(), resp)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []LevelResponse{
+		{Logger: "root", Level: "warn"},
+		{Logger: "billing", Level: "debug"},
+	}, resp.Loggers)
+}
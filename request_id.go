@@ -0,0 +1,193 @@
+package zerolog
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// RequestIDHeader is the default header used to read and echo the request id
+	RequestIDHeader = "X-Request-Id"
+
+	// RequestIDKey is the key under which the request id is stored in the app.RequestContext
+	RequestIDKey = "request_id"
+
+	// requestIDFieldKey is the key under which the name of the request id log field is stored in the app.RequestContext
+	requestIDFieldKey = "request_id_field"
+
+	maxRequestIDLength = 128
+)
+
+type requestIDCtxKey struct{}
+
+type (
+	RequestIDOptions struct {
+		logger    *Logger
+		header    string
+		field     string
+		generator func() string
+	}
+
+	RequestIDOpt func(opts *RequestIDOptions)
+)
+
+func newRequestIDOptions(options []RequestIDOpt) *RequestIDOptions {
+	opts := &RequestIDOptions{
+		header:    RequestIDHeader,
+		field:     RequestIDKey,
+		generator: GenerateUUID,
+	}
+
+	for _, set := range options {
+		set(opts)
+	}
+
+	return opts
+}
+
+// WithRequestIDLogger allows to specify the logger the request id is attached to.
+// By default, the logger returned by GetLogger at request time is used.
+func WithRequestIDLogger(logger *Logger) RequestIDOpt {
+	return func(opts *RequestIDOptions) {
+		opts.logger = logger
+	}
+}
+
+// WithRequestIDHeader allows to specify the header the request id is read from and echoed to. By default, it is set to X-Request-Id.
+func WithRequestIDHeader(header string) RequestIDOpt {
+	return func(opts *RequestIDOptions) {
+		opts.header = header
+	}
+}
+
+// WithRequestIDField allows to specify the name of the log field holding the request id. By default, it is set to request_id.
+func WithRequestIDField(field string) RequestIDOpt {
+	return func(opts *RequestIDOptions) {
+		opts.field = field
+	}
+}
+
+// WithRequestIDGenerator allows to specify the function generating request ids when the header is absent.
+// By default, GenerateUUID is used.
+func WithRequestIDGenerator(generator func() string) RequestIDOpt {
+	return func(opts *RequestIDOptions) {
+		opts.generator = generator
+	}
+}
+
+// RequestID returns a middleware that reads the request id from the request header, or generates one when absent,
+// and attaches it to the context logger, the app.RequestContext and the response header
+func RequestID(options ...RequestIDOpt) app.HandlerFunc {
+	opts := newRequestIDOptions(options)
+
+	return func(ctx context.Context, c *app.RequestContext) {
+		id := string(c.Request.Header.Peek(opts.header))
+		if !validRequestID(id) {
+			id = opts.generator()
+		}
+
+		c.Set(RequestIDKey, id)
+		c.Set(requestIDFieldKey, opts.field)
+		c.Response.Header.Set(opts.header, id)
+
+		logger := opts.logger
+		if logger == nil {
+			logger = GetLogger()
+		}
+		if logger == nil {
+			logger = From(*zerolog.Ctx(ctx))
+		}
+
+		ctx = context.WithValue(ctx, requestIDCtxKey{}, id)
+		ctx = logger.WithField(opts.field, id).WithContext(ctx)
+
+		c.Next(ctx)
+	}
+}
+
+// GetRequestID returns the request id stored in the app.RequestContext by the RequestID middleware
+func GetRequestID(c *app.RequestContext) string {
+	return c.GetString(RequestIDKey)
+}
+
+// requestIDField returns the name of the log field holding the request id, as configured on the RequestID middleware
+func requestIDField(c *app.RequestContext) string {
+	if field := c.GetString(requestIDFieldKey); field != "" {
+		return field
+	}
+
+	return RequestIDKey
+}
+
+// RequestIDFromContext returns the request id attached to the context by the RequestID middleware
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDCtxKey{}).(string)
+	return id, ok
+}
+
+// GenerateUUID returns a random (version 4) UUID
+func GenerateUUID() string {
+	var u [16]byte
+	_, _ = rand.Read(u[:])
+
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+
+	return string(buf[:])
+}
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// GenerateULID returns a lexicographically sortable ULID based on the current time
+func GenerateULID() string {
+	var u [16]byte
+	ms := uint64(time.Now().UnixMilli())
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	binary.BigEndian.PutUint32(u[2:6], uint32(ms))
+	_, _ = rand.Read(u[6:])
+
+	hi := binary.BigEndian.Uint64(u[:8])
+	lo := binary.BigEndian.Uint64(u[8:])
+
+	var buf [26]byte
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+
+	return string(buf[:])
+}
+
+// validRequestID reports whether an incoming request id is safe to propagate into logs and headers
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,144 @@
+package zerolog
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/config"
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/cloudwego/hertz/pkg/common/json"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/cloudwego/hertz/pkg/route"
+	"github.com/stretchr/testify/assert"
+)
+
+type RequestIDLog struct {
+	Level     string `json:"level"`
+	RequestID string `json:"request_id"`
+	Message   string `json:"message"`
+}
+
+func newRequestIDEngine(l *Logger, options ...RequestIDOpt) (*route.Engine, *string, *string) {
+	var (
+		fromRequestContext string
+		fromContext        string
+	)
+
+	engine := route.NewEngine(config.NewOptions([]config.Option{}))
+	engine.Use(RequestID(options...))
+	engine.GET("/", func(ctx context.Context, c *app.RequestContext) {
+		fromRequestContext = GetRequestID(c)
+		fromContext, _ = RequestIDFromContext(ctx)
+		l.CtxInfof(ctx, "handled")
+	})
+
+	return engine, &fromRequestContext, &fromContext
+}
+
+func TestRequestIDFromHeader(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithLevel(hlog.LevelInfo))
+	engine, fromRequestContext, fromContext := newRequestIDEngine(l, WithRequestIDLogger(l))
+
+	w := ut.PerformRequest(engine, "GET", "/", nil, ut.Header{Key: RequestIDHeader, Value: "abc-123"})
+
+	log := &RequestIDLog{}
+	err := json.Unmarshal(b.Bytes(), log)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc-123", log.RequestID)
+	assert.Equal(t, "handled", log.Message)
+	assert.Equal(t, "abc-123", *fromRequestContext)
+	assert.Equal(t, "abc-123", *fromContext)
+	assert.Equal(t, "abc-123", w.Header().Get(RequestIDHeader))
+}
+
+func TestRequestIDGenerated(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithLevel(hlog.LevelInfo))
+	engine, fromRequestContext, _ := newRequestIDEngine(l, WithRequestIDLogger(l))
+
+	w := ut.PerformRequest(engine, "GET", "/", nil)
+
+	log := &RequestIDLog{}
+	err := json.Unmarshal(b.Bytes(), log)
+
+	assert.NoError(t, err)
+	assert.Len(t, log.RequestID, 36)
+	assert.Equal(t, log.RequestID, *fromRequestContext)
+	assert.Equal(t, log.RequestID, w.Header().Get(RequestIDHeader))
+}
+
+func TestRequestIDInvalidHeader(t *testing.T) {
+	l := New(WithOutput(&bytes.Buffer{}))
+	engine, fromRequestContext, _ := newRequestIDEngine(l, WithRequestIDGenerator(func() string {
+		return "generated"
+	}))
+
+	ut.PerformRequest(engine, "GET", "/", nil, ut.Header{Key: RequestIDHeader, Value: strings.Repeat("a", 200)})
+	assert.Equal(t, "generated", *fromRequestContext)
+
+	ut.PerformRequest(engine, "GET", "/", nil, ut.Header{Key: RequestIDHeader, Value: "a b"})
+	assert.Equal(t, "generated", *fromRequestContext)
+}
+
+func TestRequestIDOptions(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithLevel(hlog.LevelInfo))
+	engine, _, _ := newRequestIDEngine(l,
+		WithRequestIDLogger(l),
+		WithRequestIDHeader("X-Trace"),
+		WithRequestIDField("rid"),
+	)
+
+	w := ut.PerformRequest(engine, "GET", "/", nil, ut.Header{Key: "X-Trace", Value: "xyz"})
+
+	assert.Equal(t, `{"level":"info","rid":"xyz","message":"handled"}
+`, b.String())
+	assert.Equal(t, "xyz", w.Header().Get("X-Trace"))
+}
+
+func TestRequestIDAccessLogger(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithLevel(hlog.LevelInfo))
+	engine := newTestEngine(RequestID(WithRequestIDLogger(l)), AccessLogger(WithAccessLogger(l)))
+
+	ut.PerformRequest(engine, "GET", "/users/42", nil, ut.Header{Key: RequestIDHeader, Value: "abc-123"})
+
+	assert.Contains(t, b.String(), `"request_id":"abc-123"`)
+}
+
+func TestRequestIDAccessLoggerField(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b), WithLevel(hlog.LevelInfo))
+	engine := newTestEngine(RequestID(WithRequestIDLogger(l), WithRequestIDField("rid")), AccessLogger(WithAccessLogger(l)))
+
+	ut.PerformRequest(engine, "GET", "/users/42", nil, ut.Header{Key: RequestIDHeader, Value: "abc-123"})
+
+	assert.Contains(t, b.String(), `"rid":"abc-123"`)
+	assert.NotContains(t, b.String(), `"request_id"`)
+}
+
+func TestGenerateUUID(t *testing.T) {
+	re := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	id := GenerateUUID()
+
+	assert.Regexp(t, re, id)
+	assert.NotEqual(t, id, GenerateUUID())
+}
+
+func TestGenerateULID(t *testing.T) {
+	re := regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)
+
+	first := GenerateULID()
+	second := GenerateULID()
+
+	assert.Regexp(t, re, first)
+	assert.NotEqual(t, first, second)
+	assert.LessOrEqual(t, first[:10], second[:10])
+}
@@ -0,0 +1,285 @@
+package zerolog
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const compressedSuffix = ".gz"
+
+type (
+	// rotatedFile is a file rotated by a FileWriter
+	rotatedFile struct {
+		path string
+		time time.Time
+		size int64
+	}
+
+	// cleanupResult summarizes a retention run
+	cleanupResult struct {
+		compressed   int
+		removed      int
+		removedBytes int64
+		errors       int
+		err          error
+	}
+)
+
+// WithCompression gzip compresses rotated files in the background
+func WithCompression() FileWriterOpt {
+	return func(opts *FileWriterOptions) {
+		opts.compress = true
+	}
+}
+
+// WithMaxAge deletes rotated files older than age, based on the timestamp in their name
+func WithMaxAge(age time.Duration) FileWriterOpt {
+	return func(opts *FileWriterOptions) {
+		opts.maxAge = age
+	}
+}
+
+// WithMaxBackups keeps at most count rotated files, deleting the oldest ones
+func WithMaxBackups(count int) FileWriterOpt {
+	return func(opts *FileWriterOptions) {
+		opts.maxBackups = count
+	}
+}
+
+// WithMaxTotalSize keeps the total size of rotated files at or below size bytes, deleting the oldest ones.
+// The size of the current file is not included.
+func WithMaxTotalSize(size int64) FileWriterOpt {
+	return func(opts *FileWriterOptions) {
+		opts.maxTotalSize = size
+	}
+}
+
+// WithRetentionReporter allows to specify the logger that retention runs are reported through.
+// By default, the logger returned by GetLogger is used.
+func WithRetentionReporter(logger *Logger) FileWriterOpt {
+	return func(opts *FileWriterOptions) {
+		opts.reporter = logger
+	}
+}
+
+func (opts *FileWriterOptions) retention() bool {
+	return opts.compress || opts.maxAge > 0 || opts.maxBackups > 0 || opts.maxTotalSize > 0
+}
+
+// Cleanup compresses and deletes rotated files according to the retention options and reports what was done.
+// It runs in the background after every rotation, so calling it is only needed to force a run.
+func (w *FileWriter) Cleanup() error {
+	w.cleanupMu.Lock()
+	defer w.cleanupMu.Unlock()
+
+	result := w.cleanup()
+	w.report(result)
+
+	return result.err
+}
+
+func (w *FileWriter) runCleanup() {
+	defer close(w.cleanupDone)
+
+	for range w.cleanupCh {
+		_ = w.Cleanup()
+	}
+}
+
+func (w *FileWriter) cleanup() cleanupResult {
+	var result cleanupResult
+
+	files, err := w.rotatedFiles()
+	if err != nil {
+		result.fail(err)
+		return result
+	}
+
+	if w.opts.compress {
+		for i, file := range files {
+			if strings.HasSuffix(file.path, compressedSuffix) {
+				continue
+			}
+
+			size, err := compressFile(file.path)
+			if err != nil {
+				result.fail(err)
+				continue
+			}
+
+			files[i].path += compressedSuffix
+			files[i].size = size
+			result.compressed++
+		}
+	}
+
+	cutoff := w.now().Add(-w.opts.maxAge)
+	var total int64
+	for i, file := range files {
+		total += file.size
+
+		keep := (w.opts.maxBackups <= 0 || i < w.opts.maxBackups) &&
+			(w.opts.maxAge <= 0 || !file.time.Before(cutoff)) &&
+			(w.opts.maxTotalSize <= 0 || total <= w.opts.maxTotalSize)
+		if keep {
+			continue
+		}
+
+		if err := os.Remove(file.path); err != nil {
+			result.fail(err)
+			continue
+		}
+		result.removed++
+		result.removedBytes += file.size
+	}
+
+	return result
+}
+
+func (w *FileWriter) report(result cleanupResult) {
+	if result.compressed == 0 && result.removed == 0 && result.errors == 0 {
+		return
+	}
+
+	logger := w.opts.reporter
+	if logger == nil {
+		logger = GetLogger()
+	}
+	if logger == nil {
+		return
+	}
+
+	event := logger.current().Info()
+	if result.err != nil {
+		event = logger.current().Error().Err(result.err).Int("errors", result.errors)
+	}
+
+	event.
+		Str("path", w.path).
+		Int("compressed", result.compressed).
+		Int("removed", result.removed).
+		Int64("removed_bytes", result.removedBytes).
+		Msg("log retention completed")
+}
+
+func (r *cleanupResult) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+	r.errors++
+}
+
+// rotatedFiles returns the files rotated from the path of the writer, newest first
+func (w *FileWriter) rotatedFiles() ([]rotatedFile, error) {
+	ext := filepath.Ext(w.path)
+	prefix := strings.TrimSuffix(filepath.Base(w.path), ext) + "-"
+	dir := filepath.Dir(w.path)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]rotatedFile, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), compressedSuffix)
+		if !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+
+		t, ok := w.parseRotationTime(strings.TrimSuffix(stamp, ext))
+		if !ok {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		files = append(files, rotatedFile{path: filepath.Join(dir, name), time: t, size: info.Size()})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].time.Equal(files[j].time) {
+			return files[i].path > files[j].path
+		}
+		return files[i].time.After(files[j].time)
+	})
+
+	return files, nil
+}
+
+// parseRotationTime parses the timestamp of a rotated file name, ignoring the counter added on collisions
+func (w *FileWriter) parseRotationTime(stamp string) (time.Time, bool) {
+	if t, err := time.ParseInLocation(w.opts.timeFormat, stamp, time.Local); err == nil {
+		return t, true
+	}
+
+	i := strings.LastIndexByte(stamp, '.')
+	if i < 0 {
+		return time.Time{}, false
+	}
+	if _, err := strconv.Atoi(stamp[i+1:]); err != nil {
+		return time.Time{}, false
+	}
+
+	t, err := time.ParseInLocation(w.opts.timeFormat, stamp[:i], time.Local)
+	return t, err == nil
+}
+
+// compressFile gzip compresses path to path.gz, removes path and returns the compressed size
+func compressFile(path string) (int64, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	tmp := path + compressedSuffix + ".tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return 0, err
+	}
+
+	zw := gzip.NewWriter(dst)
+	if _, err = io.Copy(zw, src); err == nil {
+		err = zw.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	_ = src.Close()
+	if err != nil {
+		_ = os.Remove(tmp)
+		return 0, err
+	}
+
+	if err := os.Rename(tmp, path+compressedSuffix); err != nil {
+		_ = os.Remove(tmp)
+		return 0, err
+	}
+
+	compressed, err := os.Stat(path + compressedSuffix)
+	if err != nil {
+		return 0, err
+	}
+
+	return compressed.Size(), os.Remove(path)
+}
@@ -0,0 +1,174 @@
+package zerolog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/hertz/pkg/common/json"
+	"github.com/stretchr/testify/assert"
+)
+
+type RetentionLog struct {
+	Level        string `json:"level"`
+	Path         string `json:"path"`
+	Compressed   int    `json:"compressed"`
+	Removed      int    `json:"removed"`
+	RemovedBytes int64  `json:"removed_bytes"`
+	Message      string `json:"message"`
+}
+
+func writeRotated(t *testing.T, dir string, stamp time.Time, content string) string {
+	path := filepath.Join(dir, "app-"+stamp.Format(DefaultRotationTimeFormat)+".log")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func newRetentionWriter(t *testing.T, dir string, now time.Time, options ...FileWriterOpt) *FileWriter {
+	w, err := NewFileWriter(filepath.Join(dir, "app.log"), append(options, WithRetentionReporter(New(WithOutput(io.Discard))))...)
+	assert.NoError(t, err)
+	w.now = func() time.Time { return now }
+	return w
+}
+
+func TestCleanupCompression(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2022, 11, 11, 10, 0, 0, 0, time.Local)
+	rotated := writeRotated(t, dir, now.Add(-time.Hour), "rotated\n")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "other.log"), []byte("other\n"), 0o644))
+
+	w := newRetentionWriter(t, dir, now)
+	w.opts.compress = true
+	b := &bytes.Buffer{}
+	w.opts.reporter = New(WithOutput(b))
+
+	assert.NoError(t, w.Cleanup())
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"app-2022-11-11T09-00-00.000.log.gz", "app.log", "other.log"}, readDir(t, dir))
+
+	f, err := os.Open(rotated + ".gz")
+	assert.NoError(t, err)
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	assert.NoError(t, err)
+	content, err := io.ReadAll(zr)
+	assert.NoError(t, err)
+	assert.Equal(t, "rotated\n", string(content))
+
+	log := &RetentionLog{}
+	err = json.Unmarshal(b.Bytes(), log)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "info", log.Level)
+	assert.Equal(t, 1, log.Compressed)
+	assert.Equal(t, 0, log.Removed)
+	assert.Equal(t, "log retention completed", log.Message)
+}
+
+func TestCleanupMaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2022, 11, 11, 10, 0, 0, 0, time.Local)
+	writeRotated(t, dir, now.Add(-3*time.Hour), "3\n")
+	writeRotated(t, dir, now.Add(-2*time.Hour), "2\n")
+	writeRotated(t, dir, now.Add(-time.Hour), "1\n")
+
+	w := newRetentionWriter(t, dir, now)
+	w.opts.maxBackups = 2
+
+	assert.NoError(t, w.Cleanup())
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{
+		"app-2022-11-11T08-00-00.000.log",
+		"app-2022-11-11T09-00-00.000.log",
+		"app.log",
+	}, readDir(t, dir))
+}
+
+func TestCleanupMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2022, 11, 11, 10, 0, 0, 0, time.Local)
+	writeRotated(t, dir, now.Add(-48*time.Hour), "old\n")
+	writeRotated(t, dir, now.Add(-time.Hour), "new\n")
+
+	w := newRetentionWriter(t, dir, now)
+	w.opts.maxAge = 24 * time.Hour
+
+	assert.NoError(t, w.Cleanup())
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"app-2022-11-11T09-00-00.000.log", "app.log"}, readDir(t, dir))
+}
+
+func TestCleanupMaxTotalSize(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2022, 11, 11, 10, 0, 0, 0, time.Local)
+	writeRotated(t, dir, now.Add(-3*time.Hour), "0123456789")
+	writeRotated(t, dir, now.Add(-2*time.Hour), "0123456789")
+	writeRotated(t, dir, now.Add(-time.Hour), "0123456789")
+
+	w := newRetentionWriter(t, dir, now)
+	b := &bytes.Buffer{}
+	w.opts.reporter = New(WithOutput(b))
+	w.opts.maxTotalSize = 25
+
+	assert.NoError(t, w.Cleanup())
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{
+		"app-2022-11-11T08-00-00.000.log",
+		"app-2022-11-11T09-00-00.000.log",
+		"app.log",
+	}, readDir(t, dir))
+
+	log := &RetentionLog{}
+	err := json.Unmarshal(b.Bytes(), log)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, log.Removed)
+	assert.Equal(t, int64(10), log.RemovedBytes)
+}
+
+func TestCleanupAfterRotation(t *testing.T) {
+	dir := t.TempDir()
+
+	w, err := NewFileWriter(filepath.Join(dir, "app.log"),
+		WithMaxFileSize(10),
+		WithCompression(),
+		WithMaxBackups(1),
+		WithRetentionReporter(New(WithOutput(io.Discard))),
+	)
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, _ = w.Write([]byte("12345678\n"))
+	}
+
+	assert.Eventually(t, func() bool {
+		names := readDir(t, dir)
+		return len(names) == 2 && filepath.Ext(names[0]) == ".gz"
+	}, time.Second, 10*time.Millisecond)
+	assert.NoError(t, w.Close())
+}
+
+func TestParseRotationTime(t *testing.T) {
+	w := &FileWriter{opts: newFileWriterOptions(nil)}
+
+	expected := time.Date(2022, 11, 11, 10, 0, 0, 0, time.Local)
+
+	parsed, ok := w.parseRotationTime("2022-11-11T10-00-00.000")
+	assert.True(t, ok)
+	assert.Equal(t, expected, parsed)
+
+	parsed, ok = w.parseRotationTime("2022-11-11T10-00-00.000.3")
+	assert.True(t, ok)
+	assert.Equal(t, expected, parsed)
+
+	_, ok = w.parseRotationTime("old")
+	assert.False(t, ok)
+}
@@ -0,0 +1,146 @@
+package zerolog
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// TraceParentHeader is the W3C trace context header identifying the incoming span
+	TraceParentHeader = "traceparent"
+
+	// TraceIDFieldName is the name of the log field holding the trace id
+	TraceIDFieldName = "trace_id"
+
+	// SpanIDFieldName is the name of the log field holding the span id
+	SpanIDFieldName = "span_id"
+
+	// TraceFlagsFieldName is the name of the log field holding the trace flags
+	TraceFlagsFieldName = "trace_flags"
+)
+
+type traceContextCtxKey struct{}
+
+// TraceContext identifies the span a log belongs to, as lowercase hex strings
+type TraceContext struct {
+	TraceID    string
+	SpanID     string
+	TraceFlags string
+}
+
+// TraceExtractor returns the trace context carried by a context, e.g. the span context of an OpenTelemetry span
+type TraceExtractor func(ctx context.Context) (TraceContext, bool)
+
+// WithTraceExtractor allows to specify how the Ctx methods find the trace context of logs.
+// By default, it is set to TraceContextFromContext; nil disables trace correlation.
+func WithTraceExtractor(extractor TraceExtractor) Opt {
+	return func(opts *Options) {
+		opts.traceExtractor = extractor
+	}
+}
+
+// TraceParent returns a middleware that parses the traceparent header of the request and attaches it to the context,
+// so that the Ctx methods emit trace_id, span_id and trace_flags fields
+func TraceParent() app.HandlerFunc {
+	return func(ctx context.Context, c *app.RequestContext) {
+		if tc, ok := ParseTraceParent(string(c.Request.Header.Peek(TraceParentHeader))); ok {
+			ctx = ContextWithTraceContext(ctx, tc)
+		}
+
+		c.Next(ctx)
+	}
+}
+
+// ParseTraceParent parses a W3C traceparent header, e.g. "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+func ParseTraceParent(header string) (TraceContext, bool) {
+	// version-trace_id-parent_id-trace_flags, future versions may append fields
+	if len(header) < 55 || header[2] != '-' || header[35] != '-' || header[52] != '-' {
+		return TraceContext{}, false
+	}
+
+	version := header[:2]
+	if !isLowerHex(version) || version == "ff" || (version == "00" && len(header) != 55) ||
+		(len(header) > 55 && header[55] != '-') {
+		return TraceContext{}, false
+	}
+
+	tc := TraceContext{
+		TraceID:    header[3:35],
+		SpanID:     header[36:52],
+		TraceFlags: header[53:55],
+	}
+	if !isLowerHex(tc.TraceID) || isZeros(tc.TraceID) ||
+		!isLowerHex(tc.SpanID) || isZeros(tc.SpanID) ||
+		!isLowerHex(tc.TraceFlags) {
+		return TraceContext{}, false
+	}
+
+	return tc, true
+}
+
+// ContextWithTraceContext returns a context carrying the trace context
+func ContextWithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextCtxKey{}, tc)
+}
+
+// TraceContextFromContext returns the trace context attached to the context by ContextWithTraceContext
+// or the TraceParent middleware
+func TraceContextFromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceContextCtxKey{}).(TraceContext)
+	return tc, ok
+}
+
+// String returns the trace context formatted as a traceparent header
+func (tc TraceContext) String() string {
+	return "00-" + tc.TraceID + "-" + tc.SpanID + "-" + tc.TraceFlags
+}
+
+// Sampled reports whether the sampled flag is set
+func (tc TraceContext) Sampled() bool {
+	return len(tc.TraceFlags) == 2 && fromHex(tc.TraceFlags[1])&1 == 1
+}
+
+// traceFields adds the fields of the trace context carried by ctx to the event
+func (l *Logger) traceFields(e *zerolog.Event, ctx context.Context) *zerolog.Event {
+	extractor := l.resolve().traceExtractor
+	if extractor == nil || ctx == nil {
+		return e
+	}
+
+	tc, ok := extractor(ctx)
+	if !ok {
+		return e
+	}
+
+	return e.Str(TraceIDFieldName, tc.TraceID).Str(SpanIDFieldName, tc.SpanID).Str(TraceFlagsFieldName, tc.TraceFlags)
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !(s[i] >= '0' && s[i] <= '9' || s[i] >= 'a' && s[i] <= 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isZeros(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '0' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func fromHex(c byte) byte {
+	if c >= 'a' {
+		return c - 'a' + 10
+	}
+
+	return c - '0'
+}
@@ -0,0 +1,93 @@
+package zerolog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/stretchr/testify/assert"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceParent(t *testing.T) {
+	tc, ok := ParseTraceParent(testTraceParent)
+	assert.True(t, ok)
+	assert.Equal(t, TraceContext{
+		TraceID:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanID:     "00f067aa0ba902b7",
+		TraceFlags: "01",
+	}, tc)
+	assert.True(t, tc.Sampled())
+	assert.Equal(t, testTraceParent, tc.String())
+
+	tc, ok = ParseTraceParent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-future")
+	assert.True(t, ok)
+	assert.False(t, tc.Sampled())
+
+	for _, header := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-0g",
+		"00_4bf92f3577b34da6a3ce929d0e0e4736_00f067aa0ba902b7_01",
+	} {
+		_, ok := ParseTraceParent(header)
+		assert.False(t, ok, header)
+	}
+}
+
+func TestCtxLogTraceFields(t *testing.T) {
+	b := &bytes.Buffer{}
+	l := New(WithOutput(b))
+
+	tc, _ := ParseTraceParent(testTraceParent)
+	ctx := ContextWithTraceContext(context.Background(), tc)
+
+	l.CtxInfof(ctx, "foo %d", 1)
+	l.CtxInfow(ctx, "bar", "key", "value")
+	l.CtxInfof(context.Background(), "baz")
+	l.Info("qux")
+
+	assert.Equal(t, `{"level":"info","trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"00f067aa0ba902b7","trace_flags":"01","message":"foo 1"}
+{"level":"info","trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"00f067aa0ba902b7","trace_flags":"01","key":"value","message":"bar"}
+{"level":"info","message":"baz"}
+{"level":"info","message":"qux"}
+`, b.String())
+}
+
+func TestWithTraceExtractor(t *testing.T) {
+	b := &bytes.Buffer{}
+	extractor := func(ctx context.Context) (TraceContext, bool) {
+		return TraceContext{TraceID: "t", SpanID: "s", TraceFlags: "00"}, true
+	}
+
+	New(WithOutput(b), WithTraceExtractor(extractor)).WithField("k", "v").CtxInfof(context.Background(), "foo")
+	New(WithOutput(b), WithTraceExtractor(nil)).CtxInfof(ContextWithTraceContext(context.Background(), TraceContext{}), "bar")
+
+	assert.Equal(t, `{"level":"info","k":"v","trace_id":"t","span_id":"s","trace_flags":"00","message":"foo"}
+{"level":"info","message":"bar"}
+`, b.String())
+}
+
+func TestTraceParent(t *testing.T) {
+	b := &syncBuffer{}
+	l := New(WithOutput(b))
+
+	engine := newTestEngine(TraceParent(), RequestID(WithRequestIDLogger(l), WithRequestIDGenerator(func() string { return "abc" })))
+	engine.GET("/trace", func(ctx context.Context, c *app.RequestContext) {
+		l.CtxInfof(ctx, "handled")
+	})
+
+	ut.PerformRequest(engine, "GET", "/trace", nil, ut.Header{Key: TraceParentHeader, Value: testTraceParent})
+	ut.PerformRequest(engine, "GET", "/trace", nil, ut.Header{Key: TraceParentHeader, Value: "invalid"})
+
+	assert.Equal(t, `{"level":"info","request_id":"abc","trace_id":"4bf92f3577b34da6a3ce929d0e0e4736","span_id":"00f067aa0ba902b7","trace_flags":"01","message":"handled"}
+{"level":"info","request_id":"abc","message":"handled"}
+`, b.String())
+}
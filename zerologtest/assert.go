@@ -0,0 +1,72 @@
+package zerologtest
+
+import (
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+)
+
+// AssertLogged fails the test unless an entry matching all the matchers was logged
+func (r *Recorder) AssertLogged(t testing.TB, matchers ...Matcher) bool {
+	t.Helper()
+
+	if len(r.Filter(matchers...)) == 0 {
+		t.Errorf("expected a matching log entry, %s", describe(r.Entries()))
+		return false
+	}
+
+	return true
+}
+
+// AssertNotLogged fails the test if an entry matching all the matchers was logged
+func (r *Recorder) AssertNotLogged(t testing.TB, matchers ...Matcher) bool {
+	t.Helper()
+
+	if matched := r.Filter(matchers...); len(matched) > 0 {
+		t.Errorf("expected no matching log entry, %s", describe(matched))
+		return false
+	}
+
+	return true
+}
+
+// AssertCount fails the test unless exactly n entries matching all the matchers were logged
+func (r *Recorder) AssertCount(t testing.TB, n int, matchers ...Matcher) bool {
+	t.Helper()
+
+	if matched := r.Filter(matchers...); len(matched) != n {
+		t.Errorf("expected %d matching log entries, %s", n, describe(matched))
+		return false
+	}
+
+	return true
+}
+
+// FailOnErrors fails the test at cleanup if an entry was logged at error level or above,
+// unless it matches one of the expected matchers
+func (r *Recorder) FailOnErrors(t testing.TB, expected ...Matcher) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		var unexpected []Entry
+		for _, entry := range r.Filter(AtLeast(hlog.LevelError)) {
+			if !matchAny(entry, expected) {
+				unexpected = append(unexpected, entry)
+			}
+		}
+
+		if len(unexpected) > 0 {
+			t.Errorf("unexpected error logs, %s", describe(unexpected))
+		}
+	})
+}
+
+func matchAny(entry Entry, matchers []Matcher) bool {
+	for _, match := range matchers {
+		if match(entry) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,76 @@
+package zerologtest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeT records the failures of assertions instead of failing the test
+type fakeT struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Cleanup(fn func()) {
+	t.cleanups = append(t.cleanups, fn)
+}
+
+func (t *fakeT) runCleanups() {
+	for i := len(t.cleanups) - 1; i >= 0; i-- {
+		t.cleanups[i]()
+	}
+}
+
+func TestAssertLogged(t *testing.T) {
+	logger, r := New()
+	ft := &fakeT{}
+
+	assert.False(t, r.AssertLogged(ft, Message("foo")))
+	assert.Equal(t, []string{"expected a matching log entry, no entries were logged"}, ft.errors)
+
+	logger.Infow("foo", "id", 1)
+	ft = &fakeT{}
+	assert.True(t, r.AssertLogged(ft, Level(hlog.LevelInfo), Message("foo"), Field("id", 1)))
+	assert.False(t, r.AssertLogged(ft, Field("id", 2)))
+	assert.Equal(t, []string{"expected a matching log entry, 1 entries were logged:\n\t" +
+		`{"level":"info","id":1,"message":"foo"}`}, ft.errors)
+}
+
+func TestAssertNotLoggedAndCount(t *testing.T) {
+	logger, r := New()
+	logger.Info("foo")
+	logger.Info("foo")
+	ft := &fakeT{}
+
+	assert.True(t, r.AssertNotLogged(ft, Message("bar")))
+	assert.False(t, r.AssertNotLogged(ft, Message("foo")))
+	assert.True(t, r.AssertCount(ft, 2, Message("foo")))
+	assert.False(t, r.AssertCount(ft, 1, Message("foo")))
+	assert.Len(t, ft.errors, 2)
+}
+
+func TestFailOnErrors(t *testing.T) {
+	logger, r := New()
+	ft := &fakeT{}
+	r.FailOnErrors(ft, MessageContains("expected"))
+
+	logger.Warn("not an error")
+	logger.Error("expected failure")
+	assert.Empty(t, ft.errors)
+
+	logger.Error("boom")
+	ft.runCleanups()
+
+	assert.Equal(t, []string{"unexpected error logs, 1 entries were logged:\n\t" +
+		`{"level":"error","message":"boom"}`}, ft.errors)
+}
@@ -0,0 +1,109 @@
+package zerologtest
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/rs/zerolog"
+)
+
+// Matcher reports whether an entry matches a condition
+type Matcher func(e Entry) bool
+
+// Level matches entries logged at level. As the Logger does, trace matches debug entries and notice matches warn entries.
+func Level(level hlog.Level) Matcher {
+	name := levelName(level)
+	return func(e Entry) bool {
+		return e.Level == name
+	}
+}
+
+// AtLeast matches entries logged at level or above
+func AtLeast(level hlog.Level) Matcher {
+	min := levelRank(levelName(level))
+	return func(e Entry) bool {
+		return levelRank(e.Level) >= min
+	}
+}
+
+// Message matches entries with the message
+func Message(msg string) Matcher {
+	return func(e Entry) bool {
+		return e.Message == msg
+	}
+}
+
+// MessageContains matches entries with a message containing substr
+func MessageContains(substr string) Matcher {
+	return func(e Entry) bool {
+		return strings.Contains(e.Message, substr)
+	}
+}
+
+// Field matches entries with the field set to value. The value is compared after a round trip through JSON,
+// so Field("count", 2) matches a count field logged as an int.
+func Field(key string, value interface{}) Matcher {
+	expected := normalize(value)
+	return func(e Entry) bool {
+		actual, ok := e.Fields[key]
+		return ok && reflect.DeepEqual(actual, expected)
+	}
+}
+
+// HasField matches entries with the field
+func HasField(key string) Matcher {
+	return func(e Entry) bool {
+		_, ok := e.Fields[key]
+		return ok
+	}
+}
+
+// Not matches entries not matching matcher
+func Not(matcher Matcher) Matcher {
+	return func(e Entry) bool {
+		return !matcher(e)
+	}
+}
+
+// normalize returns value as it is decoded from JSON
+func normalize(value interface{}) interface{} {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	var normalized interface{}
+	if err := json.Unmarshal(b, &normalized); err != nil {
+		return value
+	}
+
+	return normalized
+}
+
+// levelName returns the name of the zerolog level the Logger logs hlog levels at
+func levelName(level hlog.Level) string {
+	switch level {
+	case hlog.LevelTrace, hlog.LevelDebug:
+		return zerolog.DebugLevel.String()
+	case hlog.LevelInfo:
+		return zerolog.InfoLevel.String()
+	case hlog.LevelError:
+		return zerolog.ErrorLevel.String()
+	case hlog.LevelFatal:
+		return zerolog.FatalLevel.String()
+	default:
+		return zerolog.WarnLevel.String()
+	}
+}
+
+// levelRank orders level names, unknown names ranking below every level
+func levelRank(name string) zerolog.Level {
+	level, err := zerolog.ParseLevel(name)
+	if err != nil || level == zerolog.NoLevel || level == zerolog.Disabled {
+		return zerolog.TraceLevel - 1
+	}
+
+	return level
+}
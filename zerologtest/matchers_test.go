@@ -0,0 +1,31 @@
+package zerologtest
+
+import (
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchers(t *testing.T) {
+	logger, r := New()
+
+	logger.Tracew("traced")
+	logger.Noticew("noticed")
+	logger.Infow("user created", "id", 42, "roles", []string{"admin"}, "profile", map[string]interface{}{"age": 30})
+	logger.Errorw("user deleted", "id", 43)
+
+	assert.Len(t, r.Filter(Level(hlog.LevelTrace)), 1)
+	assert.Len(t, r.Filter(Level(hlog.LevelDebug)), 1)
+	assert.Len(t, r.Filter(Level(hlog.LevelWarn), Message("noticed")), 1)
+	assert.Len(t, r.Filter(AtLeast(hlog.LevelInfo)), 3)
+	assert.Len(t, r.Filter(AtLeast(hlog.LevelError)), 1)
+	assert.Len(t, r.Filter(MessageContains("user")), 2)
+	assert.Len(t, r.Filter(Field("id", 42)), 1)
+	assert.Len(t, r.Filter(Field("id", "42")), 0)
+	assert.Len(t, r.Filter(Field("roles", []string{"admin"})), 1)
+	assert.Len(t, r.Filter(Field("profile", map[string]int{"age": 30})), 1)
+	assert.Len(t, r.Filter(HasField("id")), 2)
+	assert.Len(t, r.Filter(Not(HasField("id"))), 2)
+	assert.Len(t, r.Filter(MessageContains("user"), Not(Field("id", 42))), 1)
+}
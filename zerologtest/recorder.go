@@ -0,0 +1,161 @@
+// Package zerologtest provides an in-memory recorder and assertion helpers to test code logging through
+// a hertz-contrib-zerolog Logger.
+package zerologtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+	hertzZerolog "github.com/sillen102/hertz-contrib-zerolog"
+)
+
+// Entry is a log entry captured by a Recorder
+type Entry struct {
+	// Level is the level field, e.g. "info"
+	Level string
+	// Message is the message field
+	Message string
+	// Fields holds the other fields, decoded from JSON: numbers are float64, objects are map[string]interface{}
+	Fields map[string]interface{}
+	// Raw is the entry as written by the logger
+	Raw []byte
+}
+
+// Recorder is an io.Writer capturing the entries written by a logger. It is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// New returns a Logger writing to a new Recorder. The options are applied after the output is set,
+// so writer stages such as WithRedaction apply to recorded entries.
+func New(options ...hertzZerolog.Opt) (*hertzZerolog.Logger, *Recorder) {
+	r := NewRecorder()
+	logger := hertzZerolog.New(append([]hertzZerolog.Opt{hertzZerolog.WithOutput(r)}, options...)...)
+	return logger, r
+}
+
+// NewRecorder returns an empty Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Write records the entries in p, one per line. Lines that are not JSON objects are recorded with their raw content only.
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, line := range bytes.Split(p, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			r.entries = append(r.entries, parseEntry(line))
+		}
+	}
+
+	return len(p), nil
+}
+
+// Entries returns the recorded entries in the order they were written
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Entry(nil), r.entries...)
+}
+
+// Filter returns the recorded entries matching all the matchers
+func (r *Recorder) Filter(matchers ...Matcher) []Entry {
+	var matched []Entry
+	for _, entry := range r.Entries() {
+		if entry.Match(matchers...) {
+			matched = append(matched, entry)
+		}
+	}
+
+	return matched
+}
+
+// Len returns the number of recorded entries
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.entries)
+}
+
+// Reset discards the recorded entries
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = nil
+}
+
+// String returns the recorded entries as written, one per line
+func (r *Recorder) String() string {
+	var b strings.Builder
+	for _, entry := range r.Entries() {
+		b.Write(entry.Raw)
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+// Field returns the value of a field
+func (e Entry) Field(key string) (interface{}, bool) {
+	value, ok := e.Fields[key]
+	return value, ok
+}
+
+// Match reports whether the entry matches all the matchers
+func (e Entry) Match(matchers ...Matcher) bool {
+	for _, match := range matchers {
+		if !match(e) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the entry as written
+func (e Entry) String() string {
+	return string(e.Raw)
+}
+
+func parseEntry(line []byte) Entry {
+	entry := Entry{Raw: append([]byte(nil), line...)}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return entry
+	}
+
+	entry.Level, _ = fields[zerolog.LevelFieldName].(string)
+	entry.Message, _ = fields[zerolog.MessageFieldName].(string)
+	delete(fields, zerolog.LevelFieldName)
+	delete(fields, zerolog.MessageFieldName)
+	entry.Fields = fields
+
+	return entry
+}
+
+// describe lists the entries for failure messages
+func describe(entries []Entry) string {
+	if len(entries) == 0 {
+		return "no entries were logged"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d entries were logged:", len(entries))
+	for _, entry := range entries {
+		b.WriteString("\n\t")
+		b.Write(entry.Raw)
+	}
+
+	return b.String()
+}
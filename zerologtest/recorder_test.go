@@ -0,0 +1,74 @@
+package zerologtest
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/cloudwego/hertz/pkg/common/hlog"
+	hertzZerolog "github.com/sillen102/hertz-contrib-zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	logger, r := New(hertzZerolog.WithField("service", "api"), hertzZerolog.WithRedaction())
+
+	logger.Infow("login", "user", "alice", "password", "hunter2", "attempt", 2)
+	logger.CtxErrorf(context.Background(), "failed %d times", 3)
+
+	entries := r.Entries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "info", entries[0].Level)
+	assert.Equal(t, "login", entries[0].Message)
+	assert.Equal(t, map[string]interface{}{
+		"service":  "api",
+		"user":     "alice",
+		"password": "***",
+		"attempt":  float64(2),
+	}, entries[0].Fields)
+	assert.Equal(t, "error", entries[1].Level)
+	assert.Equal(t, "failed 3 times", entries[1].Message)
+
+	value, ok := entries[1].Field("service")
+	assert.True(t, ok)
+	assert.Equal(t, "api", value)
+
+	assert.Equal(t, `{"level":"error","service":"api","message":"failed 3 times"}`, entries[1].String())
+	assert.Equal(t, entries[0].String()+"\n"+entries[1].String()+"\n", r.String())
+}
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder()
+
+	p := []byte("{\"level\":\"info\",\"message\":\"foo\"}\nnot json\n")
+	n, err := r.Write(p)
+	assert.NoError(t, err)
+	assert.Equal(t, len(p), n)
+	assert.Equal(t, 2, r.Len())
+	assert.Equal(t, "not json", r.Entries()[1].String())
+	assert.Nil(t, r.Entries()[1].Fields)
+
+	assert.Len(t, r.Filter(Message("foo")), 1)
+
+	r.Reset()
+	assert.Equal(t, 0, r.Len())
+	assert.Empty(t, r.Entries())
+}
+
+func TestRecorderConcurrent(t *testing.T) {
+	logger, r := New(hertzZerolog.WithLevel(hlog.LevelInfo))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				logger.Info("foo")
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 100, r.Len())
+}